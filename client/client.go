@@ -0,0 +1,237 @@
+// Package client provides a typed Go client for the task-runner HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 3                      // Retries of a request rejected with 429
+	defaultRetryDelay   = time.Second            // Delay before a retry without Retry-After
+	defaultPollInterval = 500 * time.Millisecond // Interval between polls in Wait
+)
+
+// Client calls the task-runner HTTP API.
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	headers      http.Header
+	maxRetries   int
+	retryDelay   time.Duration
+	pollInterval time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// RequestOption configures a single request.
+type RequestOption func(*http.Request)
+
+// New returns a client for the API served at baseURL (e.g. "http://localhost:8080").
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("cannot create client for %q: invalid base url", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      u,
+		httpClient:   http.DefaultClient,
+		headers:      make(http.Header),
+		maxRetries:   defaultMaxRetries,
+		retryDelay:   defaultRetryDelay,
+		pollInterval: defaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithHeader adds a header sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithAPIKey authenticates every request with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.headers.Set("X-API-Key", key)
+	}
+}
+
+// WithBearerToken authenticates every request with a bearer token (e.g. a JWT).
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.headers.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithMaxRetries sets how many times a request rejected with 429 Too Many Requests is retried.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// WithRetryDelay sets the delay before a retry when the response has no Retry-After header.
+func WithRetryDelay(d time.Duration) Option {
+	return func(c *Client) {
+		if d >= 0 {
+			c.retryDelay = d
+		}
+	}
+}
+
+// WithPollInterval sets how often Wait polls the task.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.pollInterval = d
+		}
+	}
+}
+
+// WithRequestID sets the X-Request-ID header of the request.
+func WithRequestID(id string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set("X-Request-ID", id)
+	}
+}
+
+// WithRequestHeader sets a header of the request.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+// do sends a request and decodes the JSON response into out (if not nil).
+// Requests rejected with 429 are retried after the delay given by Retry-After.
+// Error responses are returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any, opts []RequestOption) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("cannot encode request body: %w", err)
+		}
+	}
+
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("cannot build request: %w", err)
+		}
+		for k, v := range c.headers {
+			req.Header[k] = v
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for _, opt := range opts {
+			opt(req)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("cannot send request: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			delay := retryAfter(resp.Header.Get("Retry-After"), c.retryDelay)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		return decodeResponse(resp, out)
+	}
+}
+
+// decodeResponse closes the response body after decoding it into out or into an *APIError.
+func decodeResponse(resp *http.Response, out any) error {
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+		apiErr := &APIError{Status: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Code == "" {
+			apiErr.Detail = strings.TrimSpace(string(data))
+		}
+		apiErr.Status = resp.StatusCode
+
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("cannot decode response: %w", err)
+	}
+
+	return nil
+}
+
+// retryAfter returns the delay given by a Retry-After header in seconds or as an HTTP date,
+// or the fallback if the header is missing or invalid.
+func retryAfter(header string, fallback time.Duration) time.Duration {
+	if header == "" {
+		return fallback
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0)
+	}
+
+	return fallback
+}
+
+// sleep pauses for d or until ctx is done, and returns ctx.Err() in the latter case.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
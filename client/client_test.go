@@ -0,0 +1,204 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/client"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+type (
+	quickFactory struct{} // quickFactory creates a task that succeeds immediately.
+	quickTask    struct{} // quickTask succeeds without doing anything.
+)
+
+type (
+	ctxFactory struct{} // ctxFactory creates a task that runs until its context is done.
+	ctxTask    struct{} // ctxTask blocks until the context is cancelled.
+)
+
+// New returns a quick task.
+func (*quickFactory) New(_ *model.Task) task.ExecutableTask {
+	return &quickTask{}
+}
+
+// Run succeeds immediately.
+func (*quickTask) Run(_ context.Context) error {
+	return nil
+}
+
+// New returns a task that waits for its context.
+func (*ctxFactory) New(_ *model.Task) task.ExecutableTask {
+	return &ctxTask{}
+}
+
+// Run blocks until the context is done and returns its error.
+func (*ctxTask) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// newServer starts the real router backed by a manager with "quick" and "ctx" task types.
+// The wrap function (optional) can intercept requests before they reach the router.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *client.Client {
+	t.Helper()
+
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("quick", &quickFactory{})
+	manager.RegisterFactoryWithConfig("ctx", &ctxFactory{}, service.TypeConfig{QueueSize: 1, Concurrency: 1})
+
+	var h http.Handler = router.InitTaskRouter(handler.NewTaskHandler(manager))
+	if wrap != nil {
+		h = wrap(h)
+	}
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(srv.URL, client.WithPollInterval(10*time.Millisecond), client.WithRetryDelay(time.Millisecond))
+	if err != nil {
+		t.Fatalf("cannot create client: %v", err)
+	}
+	return c
+}
+
+// waitForStatus polls the task until it reaches the given status or fails on timeout.
+func waitForStatus(t *testing.T, c *client.Client, id string, status client.TaskStatus) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		tsk, err := c.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tsk.Status == status {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("task %s did not reach status %q in time", id, status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestClient_Lifecycle checks creating, waiting for, listing, retrying, and deleting a task.
+func TestClient_Lifecycle(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, "quick", map[string]string{"note": "hi"}, client.WithRequestID("req-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Type != "quick" || string(created.Params) != `{"note":"hi"}` {
+		t.Errorf("unexpected created task: %+v", created)
+	}
+
+	done, err := c.Wait(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done.Status != client.TaskStatusDone || done.FinishedAt == nil {
+		t.Errorf("expected finished task, got %+v", done)
+	}
+
+	tasks, err := c.List(ctx, client.ListOptions{Status: client.TaskStatusDone, Type: "quick"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != created.ID {
+		t.Errorf("expected the created task to be listed, got %+v", tasks)
+	}
+
+	retry, err := c.Retry(ctx, created.ID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retry.RetryOf != created.ID || string(retry.Params) != `{"note":"hi"}` {
+		t.Errorf("expected a retry of the created task, got %+v", retry)
+	}
+
+	if err := c.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Get(ctx, created.ID); !errors.Is(err, client.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+// TestClient_Errors checks that error responses are mapped to the predefined errors.
+func TestClient_Errors(t *testing.T) {
+	c := newServer(t, nil)
+	ctx := context.Background()
+
+	_, err := c.Create(ctx, "unknown", nil)
+	var apiErr *client.APIError
+	if !errors.Is(err, client.ErrTaskUnknownType) || !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Errorf("expected ErrTaskUnknownType with status 400, got %v", err)
+	}
+
+	running, err := c.Create(ctx, "ctx", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForStatus(t, c, running.ID, client.TaskStatusRunning)
+
+	if err := c.Delete(ctx, running.ID); !errors.Is(err, client.ErrTaskInProgress) {
+		t.Errorf("expected ErrTaskInProgress, got %v", err)
+	}
+	if err := c.Cancel(ctx, running.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	canceled, err := c.Wait(ctx, running.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if canceled.Status != client.TaskStatusCanceled {
+		t.Errorf("expected status 'canceled', got %q", canceled.Status)
+	}
+	if err := c.Cancel(ctx, running.ID); !errors.Is(err, client.ErrTaskFinished) {
+		t.Errorf("expected ErrTaskFinished, got %v", err)
+	}
+}
+
+// TestClient_RetryAfter checks that 429 responses are retried after the Retry-After delay
+// and reported once the retries are exhausted.
+func TestClient_RetryAfter(t *testing.T) {
+	var throttled atomic.Int32
+	c := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Throttle") != "" && throttled.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	ctx := context.Background()
+
+	if _, err := c.Create(ctx, "quick", nil, client.WithRequestHeader("X-Throttle", "1")); err != nil {
+		t.Fatalf("expected the request to be retried, got %v", err)
+	}
+	if throttled.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", throttled.Load())
+	}
+
+	if _, err := c.Create(ctx, "ctx", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Create(ctx, "ctx", nil); !errors.Is(err, client.ErrTaskQueueLimitReached) {
+		t.Errorf("expected ErrTaskQueueLimitReached, got %v", err)
+	}
+}
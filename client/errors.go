@@ -0,0 +1,79 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors reported by the API, mirroring the errors of the task manager.
+// They can be matched with errors.Is on any error returned by the Client.
+var (
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrTaskInProgress        = errors.New("task in progress")
+	ErrTaskAlreadyExists     = errors.New("task already exists")
+	ErrTaskQueueLimitReached = errors.New("task queue limit reached")
+	ErrTaskUnknownType       = errors.New("task unknown type")
+	ErrTaskTypeDisabled      = errors.New("task type disabled")
+	ErrTaskInvalidParams     = errors.New("task invalid params")
+	ErrTaskFinished          = errors.New("task already finished")
+	ErrShuttingDown          = errors.New("task manager closed")
+	ErrInvalidBatch          = errors.New("task invalid batch")
+	ErrBatchNotFound         = errors.New("batch not found")
+	ErrQueuePaused           = errors.New("task type paused")
+	ErrTaskNotFinished       = errors.New("task not finished")
+	ErrDeadLetterNotFound    = errors.New("dead letter not found")
+	ErrInvalidRequest        = errors.New("invalid request")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrForbidden             = errors.New("forbidden")
+	ErrRateLimited           = errors.New("client rate limited")
+	ErrQuotaExceeded         = errors.New("client pending quota reached")
+	ErrBurstExceeded         = errors.New("client burst exceeded")
+)
+
+// codeErrors maps the problem codes of the API to the errors above.
+var codeErrors = map[string]error{
+	"task_not_found":        ErrTaskNotFound,
+	"task_in_progress":      ErrTaskInProgress,
+	"task_already_exists":   ErrTaskAlreadyExists,
+	"queue_limit_reached":   ErrTaskQueueLimitReached,
+	"unknown_task_type":     ErrTaskUnknownType,
+	"task_type_disabled":    ErrTaskTypeDisabled,
+	"invalid_params":        ErrTaskInvalidParams,
+	"task_finished":         ErrTaskFinished,
+	"shutting_down":         ErrShuttingDown,
+	"invalid_batch":         ErrInvalidBatch,
+	"batch_not_found":       ErrBatchNotFound,
+	"queue_paused":          ErrQueuePaused,
+	"task_not_finished":     ErrTaskNotFinished,
+	"dead_letter_not_found": ErrDeadLetterNotFound,
+	"invalid_request_body":  ErrInvalidRequest,
+	"invalid_query":         ErrInvalidRequest,
+	"request_too_large":     ErrInvalidRequest,
+	"unauthorized":          ErrUnauthorized,
+	"forbidden":             ErrForbidden,
+	"rate_limited":          ErrRateLimited,
+	"quota_exceeded":        ErrQuotaExceeded,
+	"burst_exceeded":        ErrBurstExceeded,
+}
+
+// APIError is an error response of the API, decoded from its problem details.
+type APIError struct {
+	Status    int    `json:"status"`     // HTTP status code
+	Code      string `json:"code"`       // Stable machine-readable error code
+	Detail    string `json:"detail"`     // Human-readable explanation
+	RequestID string `json:"request_id"` // ID of the failed request
+}
+
+// Error returns the status, code, and detail of the response.
+func (e *APIError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("api error %d (%s)", e.Status, e.Code)
+	}
+
+	return fmt.Sprintf("api error %d (%s): %s", e.Status, e.Code, e.Detail)
+}
+
+// Unwrap returns the error matching the code, so that errors.Is works with the predefined errors.
+func (e *APIError) Unwrap() error {
+	return codeErrors[e.Code]
+}
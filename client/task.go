@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TaskStatus represents the current status of a task.
+type TaskStatus string
+
+const (
+	TaskStatusPending  TaskStatus = "pending"
+	TaskStatusRunning  TaskStatus = "running"
+	TaskStatusDone     TaskStatus = "done"
+	TaskStatusFailed   TaskStatus = "failed"
+	TaskStatusCanceled TaskStatus = "canceled"
+)
+
+// IsFinal reports whether the status is terminal and will not change anymore.
+func (s TaskStatus) IsFinal() bool {
+	return s == TaskStatusDone || s == TaskStatusFailed || s == TaskStatusCanceled
+}
+
+// Task is a task as returned by the API.
+type Task struct {
+	ID               string          `json:"id"`                    // Unique task identifier
+	Type             string          `json:"type"`                  // Type of the task
+	Status           TaskStatus      `json:"status"`                // Current task status
+	CreatedAt        time.Time       `json:"created_at"`            // Task creation timestamp
+	StartedAt        *time.Time      `json:"started_at,omitempty"`  // Execution start timestamp (if started)
+	FinishedAt       *time.Time      `json:"finished_at,omitempty"` // Completion timestamp (if finished)
+	QueuedDurationMs int64           `json:"queued_duration_ms"`    // Time spent waiting in the queue, in milliseconds
+	RunDurationMs    int64           `json:"run_duration_ms"`       // Time spent running, in milliseconds
+	Duration         string          `json:"duration,omitempty"`    // Human-readable run time (if started)
+	Result           string          `json:"result,omitempty"`      // Result message or error
+	Params           json.RawMessage `json:"params,omitempty"`      // Type-specific task parameters
+	Output           json.RawMessage `json:"output,omitempty"`      // Structured output produced by the task
+	BatchID          string          `json:"batch_id,omitempty"`    // ID of the batch the task was submitted in
+	Owner            string          `json:"owner,omitempty"`       // Identity of the client that created the task
+	RequestID        string          `json:"request_id,omitempty"`  // ID of the request that queued the task
+	TraceParent      string          `json:"traceparent,omitempty"` // W3C trace context of the request that queued the task
+	RetryOf          string          `json:"retry_of,omitempty"`    // ID of the task this one retries
+	Attempts         []TaskAttempt   `json:"attempts,omitempty"`    // Previous runs of a task retried in place, oldest first
+}
+
+// TaskAttempt is a previous run of a task retried in place.
+type TaskAttempt struct {
+	QueuedAt   time.Time  `json:"queued_at"`             // When the attempt was queued
+	StartedAt  *time.Time `json:"started_at,omitempty"`  // Execution start timestamp (if started)
+	FinishedAt *time.Time `json:"finished_at,omitempty"` // Completion timestamp
+	Status     TaskStatus `json:"status"`                // Final status of the attempt
+	Result     string     `json:"result,omitempty"`      // Result message or error
+}
+
+// ListOptions filters the tasks returned by List. Zero fields match every task.
+type ListOptions struct {
+	Status TaskStatus // Only tasks with this status
+	Type   string     // Only tasks of this type
+}
+
+// createTaskRequest is the body of POST /tasks.
+type createTaskRequest struct {
+	Type   string `json:"type"`
+	Params any    `json:"params,omitempty"`
+}
+
+// Create queues a new task of the given type. Params (optional) are encoded as the JSON task payload.
+func (c *Client) Create(ctx context.Context, taskType string, params any, opts ...RequestOption) (*Task, error) {
+	var t Task
+	req := createTaskRequest{Type: taskType, Params: params}
+
+	if err := c.do(ctx, http.MethodPost, "/tasks", nil, req, &t, opts); err != nil {
+		return nil, fmt.Errorf("cannot create task with type %q: %w", taskType, err)
+	}
+
+	return &t, nil
+}
+
+// Get returns the task with the given ID.
+func (c *Client) Get(ctx context.Context, id string) (*Task, error) {
+	var t Task
+	if err := c.do(ctx, http.MethodGet, "/tasks/"+url.PathEscape(id), nil, nil, &t, nil); err != nil {
+		return nil, fmt.Errorf("cannot get task with ID %q: %w", id, err)
+	}
+
+	return &t, nil
+}
+
+// Delete removes a task that is not running.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	if err := c.do(ctx, http.MethodDelete, "/tasks/"+url.PathEscape(id), nil, nil, nil, nil); err != nil {
+		return fmt.Errorf("cannot delete task with ID %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// Cancel cancels a pending or running task. A running task ends as canceled once it returns.
+func (c *Client) Cancel(ctx context.Context, id string) error {
+	if err := c.do(ctx, http.MethodPost, "/tasks/"+url.PathEscape(id)+"/cancel", nil, nil, nil, nil); err != nil {
+		return fmt.Errorf("cannot cancel task with ID %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// retryTaskRequest is the body of POST /tasks/{id}/retry.
+type retryTaskRequest struct {
+	InPlace bool `json:"in_place"`
+}
+
+// Retry queues a finished task again and returns the queued task. By default a new task linked
+// by RetryOf is created; with inPlace the same task is re-queued and its last run kept in Attempts.
+func (c *Client) Retry(ctx context.Context, id string, inPlace bool, opts ...RequestOption) (*Task, error) {
+	var t Task
+	req := retryTaskRequest{InPlace: inPlace}
+
+	if err := c.do(ctx, http.MethodPost, "/tasks/"+url.PathEscape(id)+"/retry", nil, req, &t, opts); err != nil {
+		return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, err)
+	}
+
+	return &t, nil
+}
+
+// List returns the tasks matching the options, oldest first.
+func (c *Client) List(ctx context.Context, opts ListOptions) ([]Task, error) {
+	query := url.Values{}
+	if opts.Status != "" {
+		query.Set("status", string(opts.Status))
+	}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+
+	var tasks []Task
+	if err := c.do(ctx, http.MethodGet, "/tasks", query, nil, &tasks, nil); err != nil {
+		return nil, fmt.Errorf("cannot list tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// Wait polls the task until it reaches a final status or ctx is done, and returns its last state.
+func (c *Client) Wait(ctx context.Context, id string) (*Task, error) {
+	for {
+		t, err := c.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if t.Status.IsFinal() {
+			return t, nil
+		}
+
+		if err := sleep(ctx, c.pollInterval); err != nil {
+			return t, fmt.Errorf("cannot wait for task with ID %q: %w", id, err)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kylerqws/task-runner/client"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+)
+
+// Exit codes of the client subcommands.
+const (
+	exitOK           = 0 // Command succeeded (and the task is done, if its outcome was awaited)
+	exitError        = 1 // Request failed
+	exitUsage        = 2 // Invalid command or arguments
+	exitTaskFailed   = 3 // Awaited task ended as failed
+	exitTaskCanceled = 4 // Awaited task ended as canceled
+)
+
+const (
+	defaultServerURL    = "http://localhost:8080" // Server used when neither --server nor the env var is set
+	defaultPollInterval = time.Second             // Interval between polls of submit --wait and watch
+)
+
+// usage describes the subcommands.
+const usage = `Usage: task-runner <command> [flags] [args]
+
+Commands:
+  serve                      run the server (default when no command is given)
+  submit --type T [--param k=v ...] [--wait]
+                             create a task; with --wait, wait for it to finish
+  get <id>                   show a task
+  ls [--status S] [--type T] list tasks
+  cancel <id>                cancel a pending or running task
+  watch <id>                 print status changes until the task finishes
+  hash-key <key>             print the hash of an API key for the server config
+
+Common flags:
+  --server URL               server URL (env TASK_RUNNER_SERVER, default http://localhost:8080)
+  --api-key KEY              API key (env TASK_RUNNER_API_KEY)
+  --output table|json        output format (default table)
+
+Exit codes: 0 success, 1 request failed, 2 usage error,
+3 awaited task failed, 4 awaited task canceled.
+`
+
+// cli holds the settings and outputs shared by the client subcommands.
+type cli struct {
+	server   string
+	apiKey   string
+	output   string
+	interval time.Duration
+	client   *client.Client
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+// runCommand runs a client subcommand and returns the process exit code.
+func runCommand(ctx context.Context, name string, args []string, stdout, stderr io.Writer) int {
+	c := &cli{stdout: stdout, stderr: stderr, interval: defaultPollInterval}
+
+	switch name {
+	case "submit":
+		return c.submit(ctx, args)
+	case "get":
+		return c.get(ctx, args)
+	case "ls":
+		return c.list(ctx, args)
+	case "cancel":
+		return c.cancel(ctx, args)
+	case "watch":
+		return c.watch(ctx, args)
+	case "hash-key":
+		return c.hashKey(args)
+	case "help":
+		_, _ = fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n%s", name, usage)
+		return exitUsage
+	}
+}
+
+// submit creates a task and optionally waits for its outcome.
+func (c *cli) submit(ctx context.Context, args []string) int {
+	fs := c.flagSet("submit")
+	taskType := fs.String("type", "default", "task type")
+	params := paramFlag{}
+	fs.Var(params, "param", "task parameter as key=value, repeatable (values are parsed as JSON when valid)")
+	wait := fs.Bool("wait", false, "wait for the task to finish; the exit code reflects its outcome")
+	fs.DurationVar(&c.interval, "interval", c.interval, "poll interval of --wait")
+
+	if _, ok := c.parse(fs, args, 0); !ok {
+		return exitUsage
+	}
+
+	var payload any
+	if len(params) > 0 {
+		payload = params
+	}
+
+	t, err := c.client.Create(ctx, *taskType, payload)
+	if err != nil {
+		return c.fail(err)
+	}
+	if !*wait {
+		c.printTask(t)
+		return exitOK
+	}
+
+	if t, err = c.client.Wait(ctx, t.ID); err != nil {
+		return c.fail(err)
+	}
+	c.printTask(t)
+
+	return outcomeCode(t.Status)
+}
+
+// get shows a task.
+func (c *cli) get(ctx context.Context, args []string) int {
+	pos, ok := c.parse(c.flagSet("get"), args, 1)
+	if !ok {
+		return exitUsage
+	}
+
+	t, err := c.client.Get(ctx, pos[0])
+	if err != nil {
+		return c.fail(err)
+	}
+	c.printTask(t)
+
+	return exitOK
+}
+
+// list shows the tasks matching the filters.
+func (c *cli) list(ctx context.Context, args []string) int {
+	fs := c.flagSet("ls")
+	status := fs.String("status", "", "only tasks with this status")
+	taskType := fs.String("type", "", "only tasks of this type")
+
+	if _, ok := c.parse(fs, args, 0); !ok {
+		return exitUsage
+	}
+
+	tasks, err := c.client.List(ctx, client.ListOptions{Status: client.TaskStatus(*status), Type: *taskType})
+	if err != nil {
+		return c.fail(err)
+	}
+	c.printTasks(tasks)
+
+	return exitOK
+}
+
+// cancel cancels a task and shows its state afterwards.
+func (c *cli) cancel(ctx context.Context, args []string) int {
+	pos, ok := c.parse(c.flagSet("cancel"), args, 1)
+	if !ok {
+		return exitUsage
+	}
+
+	if err := c.client.Cancel(ctx, pos[0]); err != nil {
+		return c.fail(err)
+	}
+
+	t, err := c.client.Get(ctx, pos[0])
+	if err != nil {
+		return c.fail(err)
+	}
+	c.printTask(t)
+
+	return exitOK
+}
+
+// watch prints the task each time its status changes until it finishes.
+func (c *cli) watch(ctx context.Context, args []string) int {
+	fs := c.flagSet("watch")
+	fs.DurationVar(&c.interval, "interval", c.interval, "poll interval")
+
+	pos, ok := c.parse(fs, args, 1)
+	if !ok {
+		return exitUsage
+	}
+
+	tw := c.table()
+	var last client.TaskStatus
+
+	for {
+		t, err := c.client.Get(ctx, pos[0])
+		if err != nil {
+			return c.fail(err)
+		}
+
+		if t.Status != last {
+			last = t.Status
+			if c.output == "json" {
+				_ = json.NewEncoder(c.stdout).Encode(t)
+			} else {
+				writeRow(tw, t)
+				_ = tw.Flush()
+			}
+		}
+		if t.Status.IsFinal() {
+			return outcomeCode(t.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return c.fail(ctx.Err())
+		case <-time.After(c.interval):
+		}
+	}
+}
+
+// hashKey prints the hash of an API key in the form expected by the "auth.api_keys" config section.
+func (c *cli) hashKey(args []string) int {
+	if len(args) != 1 || args[0] == "" {
+		_, _ = fmt.Fprintln(c.stderr, "hash-key: expected 1 argument")
+		return exitUsage
+	}
+
+	_, _ = fmt.Fprintln(c.stdout, auth.HashKey(args[0]))
+	return exitOK
+}
+
+// flagSet returns a flag set with the flags shared by all client subcommands.
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	server := os.Getenv("TASK_RUNNER_SERVER")
+	if server == "" {
+		server = defaultServerURL
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.StringVar(&c.server, "server", server, "server URL")
+	fs.StringVar(&c.apiKey, "api-key", os.Getenv("TASK_RUNNER_API_KEY"), "API key")
+	fs.StringVar(&c.output, "output", "table", "output format (table, json)")
+
+	return fs
+}
+
+// parse parses flags placed before or after the positional arguments, checks the number
+// of positional arguments and the output format, and connects the client.
+// It reports false after printing the problem if the arguments are invalid.
+func (c *cli) parse(fs *flag.FlagSet, args []string, positional int) ([]string, bool) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, false
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(pos) != positional {
+		_, _ = fmt.Fprintf(c.stderr, "%s: expected %d argument(s), got %d\n", fs.Name(), positional, len(pos))
+		return nil, false
+	}
+	if c.output != "table" && c.output != "json" {
+		_, _ = fmt.Fprintf(c.stderr, "%s: unknown output format %q\n", fs.Name(), c.output)
+		return nil, false
+	}
+
+	opts := []client.Option{client.WithPollInterval(c.interval)}
+	if c.apiKey != "" {
+		opts = append(opts, client.WithAPIKey(c.apiKey))
+	}
+
+	cl, err := client.New(c.server, opts...)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "%s: %v\n", fs.Name(), err)
+		return nil, false
+	}
+	c.client = cl
+
+	return pos, true
+}
+
+// fail prints the error and returns the exit code of a failed request.
+func (c *cli) fail(err error) int {
+	_, _ = fmt.Fprintf(c.stderr, "error: %v\n", err)
+	return exitError
+}
+
+// printTask writes a single task in the selected output format.
+func (c *cli) printTask(t *client.Task) {
+	if c.output == "json" {
+		c.printJSON(t)
+		return
+	}
+
+	tw := c.table()
+	writeRow(tw, t)
+	_ = tw.Flush()
+}
+
+// printTasks writes a list of tasks in the selected output format.
+func (c *cli) printTasks(tasks []client.Task) {
+	if c.output == "json" {
+		c.printJSON(tasks)
+		return
+	}
+
+	tw := c.table()
+	for i := range tasks {
+		writeRow(tw, &tasks[i])
+	}
+	_ = tw.Flush()
+}
+
+// printJSON writes the value as indented JSON.
+func (c *cli) printJSON(v any) {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// table returns a tab writer with the header of the task table already written.
+func (c *cli) table() *tabwriter.Writer {
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tTYPE\tSTATUS\tCREATED\tDURATION\tRESULT")
+
+	return tw
+}
+
+// writeRow writes a task as a row of the task table.
+func writeRow(w io.Writer, t *client.Task) {
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		t.ID, t.Type, t.Status, t.CreatedAt.Local().Format(time.DateTime), t.Duration, t.Result)
+}
+
+// outcomeCode returns the exit code reflecting the final status of an awaited task.
+func outcomeCode(status client.TaskStatus) int {
+	switch status {
+	case client.TaskStatusFailed:
+		return exitTaskFailed
+	case client.TaskStatusCanceled:
+		return exitTaskCanceled
+	default:
+		return exitOK
+	}
+}
+
+// paramFlag collects repeated --param key=value flags into the task payload.
+type paramFlag map[string]any
+
+// String returns the parameters as JSON.
+func (p paramFlag) String() string {
+	data, _ := json.Marshal(map[string]any(p))
+	return string(data)
+}
+
+// Set adds a key=value parameter; the value is decoded as JSON if valid and kept as a string otherwise.
+func (p paramFlag) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+
+	if json.Valid([]byte(value)) {
+		p[key] = json.RawMessage(value)
+	} else {
+		p[key] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kylerqws/task-runner/client"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+type (
+	outcomeFactory struct{ err error } // outcomeFactory creates tasks that finish immediately with err.
+	outcomeTask    struct{ err error } // outcomeTask returns its error right away.
+)
+
+// New returns a task finishing with the factory error.
+func (f *outcomeFactory) New(_ *model.Task) task.ExecutableTask {
+	return &outcomeTask{err: f.err}
+}
+
+// Run returns the configured error.
+func (t *outcomeTask) Run(_ context.Context) error {
+	return t.err
+}
+
+// newTestServer starts the real router with an "ok" and a "fail" task type and returns its URL.
+func newTestServer(t *testing.T) string {
+	t.Helper()
+
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("ok", &outcomeFactory{})
+	manager.RegisterFactory("fail", &outcomeFactory{err: errors.New("boom")})
+
+	srv := httptest.NewServer(router.InitTaskRouter(handler.NewTaskHandler(manager)))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+// TestCLI_SubmitWait checks that the exit code of submit --wait reflects the task outcome.
+func TestCLI_SubmitWait(t *testing.T) {
+	server := newTestServer(t)
+
+	code, out, errOut := runCLI(t, "submit", "--server", server, "--interval", "10ms", "--type", "ok", "--param", "n=3", "--wait", "--output", "json")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (%s)", exitOK, code, errOut)
+	}
+
+	var tsk client.Task
+	if err := json.Unmarshal([]byte(out), &tsk); err != nil {
+		t.Fatalf("cannot decode output: %v", err)
+	}
+	var params struct{ N int }
+	if err := json.Unmarshal(tsk.Params, &params); err != nil || params.N != 3 || tsk.Status != client.TaskStatusDone {
+		t.Errorf("unexpected task: %+v", tsk)
+	}
+
+	if code, _, _ := runCLI(t, "submit", "--server", server, "--interval", "10ms", "--type", "fail", "--wait"); code != exitTaskFailed {
+		t.Errorf("expected exit code %d, got %d", exitTaskFailed, code)
+	}
+}
+
+// TestCLI_GetAndList checks the table output and request failures.
+func TestCLI_GetAndList(t *testing.T) {
+	server := newTestServer(t)
+
+	_, out, _ := runCLI(t, "submit", "--server", server, "--type", "ok", "--output", "json")
+	var tsk client.Task
+	if err := json.Unmarshal([]byte(out), &tsk); err != nil {
+		t.Fatalf("cannot decode output: %v", err)
+	}
+
+	code, out, _ := runCLI(t, "get", tsk.ID, "--server", server)
+	if code != exitOK || !strings.HasPrefix(out, "ID") || !strings.Contains(out, tsk.ID) {
+		t.Errorf("expected a table with the task, got %d: %q", code, out)
+	}
+
+	code, out, _ = runCLI(t, "ls", "--server", server, "--type", "ok", "--output", "json")
+	var tasks []client.Task
+	if err := json.Unmarshal([]byte(out), &tasks); code != exitOK || err != nil || len(tasks) != 1 {
+		t.Errorf("expected one listed task, got %d: %q", code, out)
+	}
+
+	if code, _, errOut := runCLI(t, "get", "missing", "--server", server); code != exitError || !strings.Contains(errOut, "task not found") {
+		t.Errorf("expected a failed request, got %d: %q", code, errOut)
+	}
+}
+
+// TestCLI_Usage checks that invalid commands and arguments are rejected.
+func TestCLI_Usage(t *testing.T) {
+	cases := [][]string{
+		{"unknown"},
+		{"get"},
+		{"ls", "--output", "yaml"},
+		{"submit", "--param", "novalue"},
+	}
+
+	for _, args := range cases {
+		if code, _, _ := runCLI(t, args[0], args[1:]...); code != exitUsage {
+			t.Errorf("%v: expected exit code %d, got %d", args, exitUsage, code)
+		}
+	}
+}
+
+// runCLI executes a subcommand with the given arguments and returns its exit code and outputs.
+func runCLI(t *testing.T, name string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := runCommand(context.Background(), name, args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
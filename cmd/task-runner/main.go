@@ -2,74 +2,235 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
-	"time"
 
+	"github.com/kylerqws/task-runner/internal/audit"
 	"github.com/kylerqws/task-runner/internal/bootstrap"
+	"github.com/kylerqws/task-runner/internal/config"
 	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/tracing"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/certs"
 	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/middleware"
 	"github.com/kylerqws/task-runner/internal/transport/http/router"
 )
 
-const serverAddr = ":8080"
-
 // main is the application entry point.
-// It initializes the task manager, HTTP server, and handles graceful shutdown.
+// Without a subcommand (or with "serve") it runs the server; other subcommands call a running server.
 func main() {
-	manager := initManager()
-	server := initServer(manager)
+	args := os.Args[1:]
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		os.Exit(serve(args))
+	}
+	if args[0] == "serve" {
+		os.Exit(serve(args[1:]))
+	}
+
+	os.Exit(runCommand(context.Background(), args[0], args[1:], os.Stdout, os.Stderr))
+}
+
+// serve loads the configuration, initializes the task manager, HTTP server, and handles graceful shutdown.
+// It returns the exit code once the dead letter export and the audit log are closed.
+func serve(args []string) int {
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatalf("Config error: %v", err)
+	}
+
+	if cfg.PrintConfig {
+		printConfig(cfg)
+		return 0
+	}
+
+	logger, err := bootstrap.NewLogger(cfg.Log, os.Stderr)
+	if err != nil {
+		log.Fatalf("Config error: %v", err)
+	}
+	// Messages of the log package, e.g. from the standard library, go to the same logger.
+	slog.SetDefault(logger)
+
+	export, err := bootstrap.OpenDeadLetterExport(cfg.DeadLetters.ExportPath)
+	if err != nil {
+		return failure("Config error", err)
+	}
+	defer export.Close()
+
+	auditLog, err := bootstrap.OpenAuditLog(cfg.Audit)
+	if err != nil {
+		return failure("Config error", err)
+	}
+	defer auditLog.Close()
+
+	authenticator, err := bootstrap.NewAuthenticator(cfg)
+	if err != nil {
+		return failure("Config error", err)
+	}
+
+	tlsConfig, reloader, err := bootstrap.ServerTLS(cfg.Server.TLS)
+	if err != nil {
+		return failure("Config error", err)
+	}
+
+	tracer := bootstrap.NewTracer(cfg.Tracing, os.Stdout)
+	manager, err := initManager(cfg, export.Hook(), logger, tracer, auditLog)
+	if err != nil {
+		_ = tracer.Shutdown(context.Background())
+		return failure("Cannot register task factories", err)
+	}
+	server, serveErr := initServer(cfg, manager, auditLog, authenticator, tlsConfig, tracer)
+
+	if reloader != nil {
+		ctx, stop := context.WithCancel(context.Background())
+		server.RegisterOnShutdown(stop)
+		go reloader.Watch(ctx, certs.DefaultWatchInterval)
+	}
 
-	waitForShutdown(server)
+	return waitForShutdown(cfg, server, serveErr, manager, tracer, args)
+}
+
+// printConfig writes the resolved configuration to stdout as indented JSON.
+func printConfig(cfg *config.Config) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(cfg); err != nil {
+		log.Fatalf("Cannot print config: %v", err)
+	}
+}
+
+// failure logs the error and returns the exit code of a failed run.
+// Unlike os.Exit, returning lets the deferred calls of serve close its files.
+func failure(msg string, err error) int {
+	slog.Error(msg, "error", err)
+	return 1
 }
 
 // initManager creates a new TaskManager and registers all available task factories.
-func initManager() *service.TaskManager {
-	manager := service.NewTaskManager()
-	bootstrap.RegisterTaskFactories(manager)
+// New dead letters are passed to the hook (optional), the lifecycle events of tasks to the logger,
+// the spans of task runs to the tracer (optional), and state-changing operations to the audit log (optional).
+func initManager(cfg *config.Config, deadLetterHook service.DeadLetterHook, logger *slog.Logger, tracer *tracing.Tracer, auditLog *audit.Log) (*service.TaskManager, error) {
+	manager := service.NewTaskManager(bootstrap.ManagerOptions(cfg, deadLetterHook, logger, tracer, auditLog)...)
+	if err := bootstrap.RegisterTaskFactories(manager, cfg); err != nil {
+		_ = manager.Shutdown(context.Background())
+		return nil, err
+	}
 
-	return manager
+	return manager, nil
 }
 
 // initServer configures and starts the HTTP server with the task routes.
-func initServer(manager *service.TaskManager) *http.Server {
+// Requests pass the configured middleware, are traced if a tracer is given, are authenticated
+// if an authenticator is given, and are served over HTTPS if TLS settings are given.
+// The audit log (optional) is served by GET /audit. The returned channel receives the error
+// that stops the server from serving, other than its shutdown.
+func initServer(cfg *config.Config, manager *service.TaskManager, auditLog *audit.Log, authenticator *auth.Authenticator, tlsConfig *tls.Config, tracer *tracing.Tracer) (*http.Server, <-chan error) {
 	taskHandler := handler.NewTaskHandler(manager)
+	taskHandler.AuditLog = auditLog
 	httpHandler := router.InitTaskRouter(taskHandler)
+	if authenticator != nil {
+		httpHandler = authenticator.Middleware(httpHandler)
+	}
+	httpHandler = middleware.Chain(
+		http.MaxBytesHandler(httpHandler, int64(cfg.Server.MaxBodyBytes)),
+		bootstrap.Middleware(cfg.Server.Middleware, slog.Default(), tracer)...,
+	)
 
 	server := &http.Server{
-		Addr:    serverAddr,
-		Handler: httpHandler,
+		Addr:              cfg.Server.Addr,
+		Handler:           httpHandler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout.Std(),
+		IdleTimeout:       cfg.Server.IdleTimeout.Std(),
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
 	}
 
+	serveErr := make(chan error, 1)
 	go func() {
-		log.Println("Server listening on", serverAddr)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("HTTP server error: %v", err)
+		var err error
+		if tlsConfig != nil {
+			slog.Info("Server listening with TLS", "addr", cfg.Server.Addr)
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			slog.Info("Server listening", "addr", cfg.Server.Addr)
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
 		}
 	}()
 
-	return server
+	return server, serveErr
 }
 
-// waitForShutdown blocks until a termination signal is received
-// and then shuts down the HTTP server gracefully.
-func waitForShutdown(server *http.Server) {
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+// reloadConfig reloads the configuration from the same sources as at startup
+// and applies the task type settings and client limits.
+// On error the current settings are kept.
+func reloadConfig(manager *service.TaskManager, args []string) {
+	cfg, err := config.Load(args)
+	if err != nil {
+		slog.Error("Config reload failed, keeping current settings", "error", err)
+		return
+	}
 
-	<-quit
-	log.Println("Shutting down server...")
+	if err := bootstrap.RegisterTaskFactories(manager, cfg); err != nil {
+		slog.Error("Config reload failed, keeping current settings", "error", err)
+		return
+	}
+	bootstrap.ApplyClientLimits(manager, cfg)
+
+	slog.Info("Config reloaded")
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// waitForShutdown blocks until a termination signal is received or the server fails to serve,
+// and then shuts down the HTTP server, the task manager, and the tracer gracefully.
+// SIGHUP received in the meantime reloads the task type configuration.
+// It returns the exit code: 1 if the server failed or could not be shut down gracefully.
+func waitForShutdown(cfg *config.Config, server *http.Server, serveErr <-chan error, manager *service.TaskManager, tracer *tracing.Tracer, args []string) int {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	code := 0
+wait:
+	for {
+		select {
+		case sig := <-quit:
+			if sig != syscall.SIGHUP {
+				break wait
+			}
+			reloadConfig(manager, args)
+		case err := <-serveErr:
+			code = failure("HTTP server error", err)
+			break wait
+		}
+	}
+	slog.Info("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Std())
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Forced shutdown: %v", err)
+		code = failure("Forced shutdown", err)
+	}
+	if err := manager.Shutdown(ctx); err != nil {
+		slog.Warn("Unfinished tasks canceled", "error", err)
+	}
+	if err := tracer.Shutdown(ctx); err != nil {
+		slog.Warn("Spans not exported", "error", err)
+	}
+
+	if code == 0 {
+		slog.Info("Server exited gracefully")
 	}
 
-	log.Println("Server exited gracefully")
+	return code
 }
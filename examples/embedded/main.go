@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kylerqws/task-runner/runner"
+)
+
+// greetParams holds the parameters of a "greet" task.
+type greetParams struct {
+	Name string `json:"name"`
+}
+
+// greetFactory creates "greet" tasks.
+type greetFactory struct{}
+
+// greetTask waits a moment and produces a greeting.
+type greetTask struct {
+	name     string
+	greeting string
+}
+
+// New creates a greet task from the task parameters.
+func (*greetFactory) New(t *runner.Task) runner.ExecutableTask {
+	var p greetParams
+	_ = json.Unmarshal(t.Params, &p)
+
+	return &greetTask{name: p.Name}
+}
+
+// ValidateParams rejects tasks without a name.
+func (*greetFactory) ValidateParams(params json.RawMessage) error {
+	var p greetParams
+	if err := json.Unmarshal(params, &p); err != nil || p.Name == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+// Run waits for a second, or until the task is canceled, and builds the greeting.
+func (t *greetTask) Run(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Second):
+	}
+
+	t.greeting = fmt.Sprintf("Hello, %s!", t.name)
+	return nil
+}
+
+// Output returns the greeting stored in the task output.
+func (t *greetTask) Output() any {
+	return map[string]string{"greeting": t.greeting}
+}
+
+// main embeds the runner with a custom "greet" task type, submits a task in-process,
+// and serves the task API under /runner/ next to the application's own routes.
+func main() {
+	r := runner.New()
+
+	err := r.Register("greet", &greetFactory{},
+		runner.WithDescription("Greets someone after a short pause."),
+		runner.WithParamsSchema(json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}`)),
+		runner.WithLimits(runner.Limits{QueueSize: 10, Concurrency: 2, Timeout: 5 * time.Second}),
+	)
+	if err != nil {
+		log.Fatalf("Cannot register task type: %v", err)
+	}
+
+	t, err := r.Submit("greet", greetParams{Name: "world"})
+	if err != nil {
+		log.Fatalf("Cannot submit task: %v", err)
+	}
+	if t, err = r.Wait(context.Background(), t.ID); err != nil {
+		log.Fatalf("Cannot wait for task: %v", err)
+	}
+	log.Printf("Task %s finished as %s: %s", t.ID, t.Status, t.Output)
+
+	mux := http.NewServeMux()
+	mux.Handle("/runner/", r.Handler("/runner"))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{Addr: ":8081", Handler: mux}
+	go func() {
+		log.Println("Listening on :8081, task API under /runner/")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("HTTP server error: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = server.Shutdown(ctx)
+	if err := r.Shutdown(ctx); err != nil {
+		log.Printf("Tasks canceled on shutdown: %v", err)
+	}
+}
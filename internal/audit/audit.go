@@ -0,0 +1,25 @@
+// Package audit stores the audit log of state-changing task operations in append-only JSONL files
+// and carries the identity of the client requesting them from the HTTP layer to the domain.
+package audit
+
+import "context"
+
+// Anonymous is the actor recorded for operations requested without an identity, e.g. without authentication.
+const Anonymous = "anonymous"
+
+// actorKey is the context key of the actor.
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the identity of the client requesting operations.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor carried by ctx, or Anonymous if there is none.
+func Actor(ctx context.Context) string {
+	if actor, _ := ctx.Value(actorKey{}).(string); actor != "" {
+		return actor
+	}
+
+	return Anonymous
+}
@@ -0,0 +1,342 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// ErrClosed is returned when recording to or querying a closed log.
+var ErrClosed = errors.New("audit log closed")
+
+// rotatedLayout is the time layout appended to the path of rotated files; it sorts in time order.
+const rotatedLayout = "20060102T150405.000000000Z"
+
+// Log appends audit entries to a JSONL file, rotating it once it grows beyond the max size.
+// Rotated files are renamed with the rotation time appended to the path and are never written again.
+type Log struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64    // Size in bytes beyond which the file is rotated (0 disables rotation)
+	maxFiles int      // Max rotated files kept (0 keeps all)
+	file     *os.File // Current file (nil if it could not be reopened after a rotation)
+	size     int64    // Size of the current file
+	sync     bool     // Sync the file to disk after every write
+	closed   bool
+}
+
+// Option configures a Log.
+type Option func(*Log)
+
+// WithMaxSize rotates the file before a write would make it grow beyond n bytes.
+// A file is never rotated while empty, so entries larger than n are still recorded.
+func WithMaxSize(n int64) Option {
+	return func(l *Log) {
+		l.maxSize = max(n, 0)
+	}
+}
+
+// WithMaxFiles keeps at most n rotated files, removing the oldest ones after a rotation.
+// Every rotated file is kept by default, or with n = 0.
+func WithMaxFiles(n int) Option {
+	return func(l *Log) {
+		l.maxFiles = max(n, 0)
+	}
+}
+
+// WithSync sets whether the file is synced to disk after every write, which is the default.
+// Without syncing, writes are faster but the last entries may be lost if the machine crashes.
+func WithSync(sync bool) Option {
+	return func(l *Log) {
+		l.sync = sync
+	}
+}
+
+// Open opens (or creates) the JSONL file at path for appending.
+func Open(path string, opts ...Option) (*Log, error) {
+	l := &Log{path: path, sync: true}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Record appends the entries to the file in a single write and syncs it to disk (unless disabled by WithSync),
+// rotating the file first if the entries would make it grow beyond the max size.
+// The entries are recorded only if it returns nil; a failed write is cut off the file.
+func (l *Log) Record(entries ...*model.AuditEntry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("cannot encode audit entry: %w", err)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return err
+		}
+	}
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(buf.Len()) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(buf.Bytes())
+	if err == nil && l.sync {
+		err = l.file.Sync()
+	}
+	if err != nil {
+		// A partial line would be merged with the next entry, so the file is cut back to the last complete one.
+		if terr := l.file.Truncate(l.size); terr != nil {
+			l.size += int64(n)
+		}
+		return fmt.Errorf("cannot write audit log %q: %w", l.path, err)
+	}
+
+	l.size += int64(n)
+
+	return nil
+}
+
+// Filter selects the entries returned by Query.
+type Filter struct {
+	TaskID string    // Entries of the task and of the retries created from it (empty matches every entry)
+	Since  time.Time // Entries recorded at or after this time (zero matches every entry)
+	Limit  int       // Max entries returned, the most recent are kept (0 returns all)
+}
+
+// matches reports whether the entry is selected by the filter.
+func (f Filter) matches(e *model.AuditEntry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+
+	return f.TaskID == "" || e.TaskID == f.TaskID || e.RetryOf == f.TaskID
+}
+
+// Query returns the entries matching the filter from the rotated files and the current one, oldest first.
+// Rotated files whose rotation time is before the Since bound of the filter hold no matching entry and are not read.
+// The files are read as they were when it was called; entries recorded meanwhile are not returned.
+func (l *Log) Query(filter Filter) ([]*model.AuditEntry, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, ErrClosed
+	}
+	paths, err := l.rotated()
+	var current *os.File
+	if err == nil {
+		current, err = os.Open(l.path)
+	}
+	size := l.size
+	l.mu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot query audit log %q: %w", l.path, err)
+	}
+	defer current.Close()
+
+	var entries []*model.AuditEntry
+	for _, path := range paths {
+		if rotatedAt, ok := l.rotatedTime(path); ok && rotatedAt.Before(filter.Since) {
+			continue // Every entry of the file was recorded before it was rotated
+		}
+
+		file, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // Removed by a rotation meanwhile
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot query audit log %q: %w", path, err)
+		}
+
+		entries, err = readEntries(file, path, filter, entries)
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return readEntries(io.LimitReader(current, size), l.path, filter, entries)
+}
+
+// Close closes the current file. It does nothing if the log is nil or already closed.
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	if l.file == nil {
+		return nil
+	}
+
+	return l.file.Close()
+}
+
+// open opens the current file for appending and reads its size.
+// WARNING: Must be called with l.mu held.
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot open audit log %q: %w", l.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("cannot open audit log %q: %w", l.path, err)
+	}
+
+	l.file, l.size = file, info.Size()
+
+	return nil
+}
+
+// rotate renames the current file with the rotation time appended, opens a new one,
+// and removes the oldest rotated files beyond the max number.
+// WARNING: Must be called with l.mu held.
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		l.file = nil
+		return fmt.Errorf("cannot rotate audit log %q: %w", l.path, err)
+	}
+	l.file = nil
+
+	if err := os.Rename(l.path, l.rotatedPath()); err != nil {
+		return errors.Join(fmt.Errorf("cannot rotate audit log %q: %w", l.path, err), l.open())
+	}
+	if err := l.open(); err != nil {
+		return err
+	}
+	if l.maxFiles == 0 {
+		return nil
+	}
+
+	paths, err := l.rotated()
+	if err != nil {
+		return fmt.Errorf("cannot rotate audit log %q: %w", l.path, err)
+	}
+	for len(paths) > l.maxFiles {
+		if err := os.Remove(paths[0]); err != nil {
+			return fmt.Errorf("cannot remove rotated audit log %q: %w", paths[0], err)
+		}
+		paths = paths[1:]
+	}
+
+	return nil
+}
+
+// rotatedPath returns the path of the current file rotated now, moved forward if a rotated file already has it.
+func (l *Log) rotatedPath() string {
+	now := time.Now().UTC()
+	for {
+		path := l.path + "." + now.Format(rotatedLayout)
+		if _, err := os.Lstat(path); err != nil {
+			return path
+		}
+		now = now.Add(time.Nanosecond)
+	}
+}
+
+// rotated returns the paths of the rotated files, oldest first.
+func (l *Log) rotated() ([]string, error) {
+	dir := filepath.Dir(l.path)
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, item := range items {
+		path := filepath.Join(dir, item.Name())
+		if _, ok := l.rotatedTime(path); !ok || item.IsDir() {
+			continue
+		}
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// rotatedTime returns the rotation time appended to the path of a rotated file,
+// or false if the path is not one of a rotated file of the log.
+func (l *Log) rotatedTime(path string) (time.Time, bool) {
+	suffix, ok := strings.CutPrefix(filepath.Base(path), filepath.Base(l.path)+".")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(rotatedLayout, suffix)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// readEntries appends the entries of a JSONL file matching the filter to entries,
+// dropping the oldest ones beyond the limit of the filter.
+// A last line without a newline is the rest of an interrupted write and is ignored.
+func readEntries(r io.Reader, path string, filter Filter, entries []*model.AuditEntry) ([]*model.AuditEntry, error) {
+	br := bufio.NewReader(r)
+
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read audit log %q: %w", path, err)
+		}
+
+		e := &model.AuditEntry{}
+		if err := json.Unmarshal(data, e); err != nil {
+			return nil, fmt.Errorf("cannot decode audit log %q, line %d: %w", path, line, err)
+		}
+		if !filter.matches(e) {
+			continue
+		}
+
+		entries = append(entries, e)
+		if filter.Limit > 0 && len(entries) > filter.Limit {
+			entries = entries[1:]
+		}
+	}
+}
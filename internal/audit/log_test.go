@@ -0,0 +1,183 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// entry returns an audit entry of the action on the task.
+func entry(taskID string, action model.AuditAction) *model.AuditEntry {
+	return &model.AuditEntry{
+		Time:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Actor:    "alice",
+		Action:   action,
+		TaskID:   taskID,
+		TaskType: "default",
+	}
+}
+
+// TestLog checks that recorded entries are queried back by task, oldest first, and within the limit.
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := audit.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+
+	retry := entry("b", model.AuditActionRetry)
+	retry.RetryOf = "a"
+	for _, e := range []*model.AuditEntry{entry("a", model.AuditActionCreate), entry("c", model.AuditActionCreate), retry} {
+		if err := l.Record(e); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	entries, err := l.Query(audit.Filter{TaskID: "a"})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != model.AuditActionCreate || entries[1].TaskID != "b" {
+		t.Fatalf("expected the creation of a and its retry, got %+v", entries)
+	}
+
+	entries, err = l.Query(audit.Filter{Limit: 2})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].TaskID != "c" || entries[1].TaskID != "b" {
+		t.Errorf("expected the 2 most recent entries, got %+v", entries)
+	}
+
+	// A line cut by an interrupted write is ignored.
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	_, _ = file.WriteString(`{"time":`)
+	_ = file.Close()
+	l2, err := audit.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l2.Close()
+	if entries, err := l2.Query(audit.Filter{}); err != nil || len(entries) != 3 {
+		t.Errorf("expected 3 entries, got %d, %v", len(entries), err)
+	}
+
+	_ = l.Close()
+	if err := l.Record(entry("a", model.AuditActionDelete)); !errors.Is(err, audit.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+// TestLog_NoSync checks that entries are recorded and queried back without syncing.
+func TestLog_NoSync(t *testing.T) {
+	l, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"), audit.WithSync(false))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+
+	if err := l.Record(entry("a", model.AuditActionCreate), entry("a", model.AuditActionDelete)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if entries, err := l.Query(audit.Filter{TaskID: "a"}); err != nil || len(entries) != 2 {
+		t.Errorf("expected 2 entries, got %d, %v", len(entries), err)
+	}
+}
+
+// TestLog_Rotation checks that the file is rotated beyond the max size, that the oldest rotated files
+// are removed beyond the max number, and that rotated files are still queried.
+func TestLog_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+
+	// Every entry is larger than half of the max size, so each one starts a new file.
+	size := int64(len(`{"time":"2026-01-02T03:04:05Z","actor":"alice","action":"create","task_id":"a","task_type":"default"}`)) + 1
+	l, err := audit.Open(path, audit.WithMaxSize(size+size/2), audit.WithMaxFiles(2))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if err := l.Record(entry(id, model.AuditActionCreate)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	files, _ := filepath.Glob(path + "*")
+	if len(files) != 3 {
+		t.Fatalf("expected the current file and 2 rotated ones, got %v", files)
+	}
+	for _, f := range files {
+		if info, _ := os.Stat(f); info.Size() != size {
+			t.Errorf("expected %s to hold one entry of %d bytes, got %d", f, size, info.Size())
+		}
+	}
+
+	entries, err := l.Query(audit.Filter{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.TaskID)
+	}
+	if got := strings.Join(ids, ","); got != "b,c,d" {
+		t.Errorf("expected entries b,c,d, got %s", got)
+	}
+}
+
+// TestLog_Since checks that entries before the since bound are skipped,
+// and that rotated files rotated before it are not read at all.
+func TestLog_Since(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	// The file was rotated before the bound; it is not valid JSONL, so reading it would fail the query.
+	if err := os.WriteFile(path+".20251231T000000.000000000Z", []byte("not json\n"), 0o600); err != nil {
+		t.Fatalf("cannot write rotated file: %v", err)
+	}
+
+	l, err := audit.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer l.Close()
+
+	old := entry("a", model.AuditActionCreate)
+	old.Time = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, e := range []*model.AuditEntry{old, entry("b", model.AuditActionCreate)} {
+		if err := l.Record(e); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	entries, err := l.Query(audit.Filter{Since: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].TaskID != "b" {
+		t.Errorf("expected only the entry of b, got %+v", entries)
+	}
+
+	if _, err := l.Query(audit.Filter{}); err == nil {
+		t.Error("expected the rotated file to be read without a since bound")
+	}
+}
+
+// TestActor checks that the actor defaults to Anonymous.
+func TestActor(t *testing.T) {
+	if got := audit.Actor(context.Background()); got != audit.Anonymous {
+		t.Errorf("expected %q, got %q", audit.Anonymous, got)
+	}
+	if got := audit.Actor(audit.WithActor(context.Background(), "alice")); got != "alice" {
+		t.Errorf("expected alice, got %q", got)
+	}
+}
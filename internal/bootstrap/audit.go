@@ -0,0 +1,16 @@
+package bootstrap
+
+import (
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/config"
+)
+
+// OpenAuditLog opens (or creates) the audit log configured by cfg for appending.
+// An empty path disables the audit log and returns nil.
+func OpenAuditLog(cfg config.AuditConfig) (*audit.Log, error) {
+	if cfg.Path == "" {
+		return nil, nil
+	}
+
+	return audit.Open(cfg.Path, audit.WithMaxSize(cfg.MaxSize), audit.WithMaxFiles(cfg.MaxFiles), audit.WithSync(cfg.Sync))
+}
@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"github.com/kylerqws/task-runner/internal/config"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+)
+
+// NewAuthenticator creates the authenticator of API clients from the configuration.
+// It returns nil if authentication is disabled.
+func NewAuthenticator(cfg *config.Config) (*auth.Authenticator, error) {
+	if !cfg.Auth.Enabled {
+		return nil, nil
+	}
+
+	keys := make([]auth.APIKey, len(cfg.Auth.APIKeys))
+	for i, k := range cfg.Auth.APIKeys {
+		keys[i] = auth.APIKey{Name: k.Name, Hash: k.Hash, Scopes: k.Scopes}
+	}
+
+	clientCerts := make([]auth.ClientCert, len(cfg.Auth.ClientCerts))
+	for i, c := range cfg.Auth.ClientCerts {
+		clientCerts[i] = auth.ClientCert{Subject: c.Subject, Name: c.Name, Scopes: c.Scopes}
+	}
+
+	return auth.New(auth.Config{
+		APIKeys:     keys,
+		JWKSFile:    cfg.Auth.JWT.JWKSFile,
+		Issuer:      cfg.Auth.JWT.Issuer,
+		Audience:    cfg.Auth.JWT.Audience,
+		ClientCerts: clientCerts,
+	})
+}
@@ -0,0 +1,31 @@
+package bootstrap
+
+import (
+	"github.com/kylerqws/task-runner/internal/config"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// ApplyClientLimits replaces the client limits of the TaskManager with the configured ones.
+func ApplyClientLimits(m *service.TaskManager, cfg *config.Config) {
+	m.SetClientLimits(clientLimits(cfg))
+}
+
+// clientLimits converts the configured client limits into service limits and per-client overrides.
+func clientLimits(cfg *config.Config) (service.ClientLimits, map[string]service.ClientLimits) {
+	overrides := make(map[string]service.ClientLimits, len(cfg.Clients.Overrides))
+	for name, l := range cfg.Clients.Overrides {
+		overrides[name] = clientLimit(l)
+	}
+
+	return clientLimit(cfg.Clients.Defaults), overrides
+}
+
+// clientLimit converts the configured limits of a client into service limits.
+func clientLimit(c config.ClientLimitsConfig) service.ClientLimits {
+	return service.ClientLimits{
+		Rate:       c.Rate,
+		Burst:      c.Burst,
+		MaxPending: c.MaxPending,
+		Weight:     c.Weight,
+	}
+}
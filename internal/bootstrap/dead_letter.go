@@ -0,0 +1,55 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// DeadLetterExport appends every new dead letter to a JSONL file for offline analysis.
+type DeadLetterExport struct {
+	file *os.File
+	enc  *json.Encoder
+}
+
+// OpenDeadLetterExport opens (or creates) the JSONL file at path for appending.
+// An empty path disables the export and returns nil.
+func OpenDeadLetterExport(path string) (*DeadLetterExport, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open dead letter export %q: %w", path, err)
+	}
+
+	return &DeadLetterExport{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+// Hook returns the dead letter hook writing to the file, or nil if the export is disabled.
+// Write errors are logged and do not affect the task manager.
+func (e *DeadLetterExport) Hook() service.DeadLetterHook {
+	if e == nil {
+		return nil
+	}
+
+	return func(d *model.DeadLetter) {
+		if err := e.enc.Encode(d); err != nil {
+			log.Printf("Cannot export dead letter %q: %v", d.Task.ID, err)
+		}
+	}
+}
+
+// Close closes the export file. It does nothing if the export is disabled.
+func (e *DeadLetterExport) Close() error {
+	if e == nil {
+		return nil
+	}
+
+	return e.file.Close()
+}
@@ -0,0 +1,14 @@
+package bootstrap
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/kylerqws/task-runner/internal/config"
+	"github.com/kylerqws/task-runner/internal/logging"
+)
+
+// NewLogger creates the application logger writing to w in the configured format and level.
+func NewLogger(cfg config.LogConfig, w io.Writer) (*slog.Logger, error) {
+	return logging.New(w, cfg.Format, cfg.Level)
+}
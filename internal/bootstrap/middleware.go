@@ -0,0 +1,44 @@
+package bootstrap
+
+import (
+	"log/slog"
+
+	"github.com/kylerqws/task-runner/internal/config"
+	"github.com/kylerqws/task-runner/internal/tracing"
+	"github.com/kylerqws/task-runner/internal/transport/http/middleware"
+)
+
+// Middleware returns the enabled HTTP middleware in the order they wrap requests: request IDs first,
+// so that the other pieces see them, then tracing (if a tracer is given), access logs, panic recovery
+// (inside the access log, so that recovered panics are logged as 500), CORS (before authentication,
+// so that preflights need no credentials), and compression.
+func Middleware(cfg config.MiddlewareConfig, logger *slog.Logger, tracer *tracing.Tracer) []middleware.Middleware {
+	var mws []middleware.Middleware
+
+	if cfg.RequestID {
+		mws = append(mws, middleware.RequestID())
+	}
+	if tracer != nil {
+		mws = append(mws, middleware.Trace(tracer))
+	}
+	if cfg.AccessLog {
+		mws = append(mws, middleware.AccessLog(logger))
+	}
+	if cfg.Recovery {
+		mws = append(mws, middleware.Recover(logger))
+	}
+	if cfg.CORS.Enabled {
+		mws = append(mws, middleware.CORS(middleware.CORSConfig{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge.Std(),
+		}))
+	}
+	if cfg.Gzip {
+		mws = append(mws, middleware.Gzip())
+	}
+
+	return mws
+}
@@ -1,24 +1,165 @@
 package bootstrap
 
 import (
-	"math/rand"
-	"time"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/config"
+	"github.com/kylerqws/task-runner/internal/domain/clock"
 	"github.com/kylerqws/task-runner/internal/domain/service"
 	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/tracing"
 )
 
-// RegisterTaskFactories registers all available task factories
-// to the provided TaskManager instance.
-func RegisterTaskFactories(m *service.TaskManager) {
-	m.RegisterFactory(task.DefaultTaskType, newDefaultTaskFactory())
+// taskTypeEntry describes how a built-in task type is built from the configuration.
+type taskTypeEntry struct {
+	name        string
+	description string
+	schema      string
+	limits      func(cfg *config.Config) config.TypeConfig
+	factory     func(cfg *config.Config, clk clock.Clock) (task.Factory, error)
 }
 
-// newDefaultTaskFactory returns a Factory for the "default" task type,
-// preconfigured with a random delay between 3 and 5 minutes.
-func newDefaultTaskFactory() task.Factory {
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	delay := time.Duration(3+rng.Intn(3)) * time.Minute
+// builtinTaskTypes lists all task types known to the application.
+var builtinTaskTypes = []taskTypeEntry{
+	{
+		name:        task.DefaultTaskType,
+		description: task.DefaultTaskDescription,
+		schema:      task.DefaultTaskParamsSchema,
+		limits:      func(cfg *config.Config) config.TypeConfig { return cfg.Tasks.Default.TypeConfig },
+		factory: func(cfg *config.Config, clk clock.Clock) (task.Factory, error) {
+			return newDefaultTaskFactory(cfg.Tasks.Default, clk), nil
+		},
+	},
+	{
+		name:        task.ExecTaskType,
+		description: task.ExecTaskDescription,
+		schema:      task.ExecTaskParamsSchema,
+		limits:      func(cfg *config.Config) config.TypeConfig { return cfg.Tasks.Exec.TypeConfig },
+		factory: func(cfg *config.Config, _ clock.Clock) (task.Factory, error) {
+			return newExecTaskFactory(cfg.Tasks.Exec)
+		},
+	},
+	{
+		name:        task.HTTPTaskType,
+		description: task.HTTPTaskDescription,
+		schema:      task.HTTPTaskParamsSchema,
+		limits:      func(cfg *config.Config) config.TypeConfig { return cfg.Tasks.HTTP.TypeConfig },
+		factory: func(cfg *config.Config, clk clock.Clock) (task.Factory, error) {
+			return newHTTPTaskFactory(cfg.Tasks.HTTP, clk), nil
+		},
+	},
+}
+
+// RegisterTaskFactories registers all enabled task factories
+// to the provided TaskManager instance using the given task settings.
+// Called again with a new configuration, it updates the factories and limits of registered types,
+// registers newly enabled ones, and unregisters disabled ones without dropping queued tasks.
+// Every factory is built before anything is applied, so on error the manager is left unchanged.
+func RegisterTaskFactories(m *service.TaskManager, cfg *config.Config) error {
+	updates := make([]service.TypeUpdate, 0, len(builtinTaskTypes))
+	for _, entry := range builtinTaskTypes {
+		limits := entry.limits(cfg)
+
+		if limits.Disabled {
+			updates = append(updates, service.TypeUpdate{Descriptor: task.Descriptor{Type: entry.name}, Disabled: true})
+			continue
+		}
+
+		desc, err := entry.descriptor(cfg, m.Clock())
+		if err != nil {
+			return fmt.Errorf("cannot configure task type %q: %w", entry.name, err)
+		}
+		updates = append(updates, service.TypeUpdate{Descriptor: desc, Config: typeConfig(limits)})
+	}
+
+	return m.ApplyTypes(updates)
+}
+
+// descriptor builds the registration descriptor of the task type using the given clock.
+func (e taskTypeEntry) descriptor(cfg *config.Config, clk clock.Clock) (task.Descriptor, error) {
+	factory, err := e.factory(cfg, clk)
+	if err != nil {
+		return task.Descriptor{}, err
+	}
+
+	return task.Descriptor{
+		Type:         e.name,
+		Description:  e.description,
+		ParamsSchema: json.RawMessage(e.schema),
+		Factory:      factory,
+	}, nil
+}
+
+// ManagerOptions converts the configuration into TaskManager options.
+// The hook (optional) receives every new dead letter, the logger the lifecycle events of tasks,
+// the tracer (optional) the spans of their queue wait and run, and the audit log (optional)
+// the state-changing operations.
+func ManagerOptions(cfg *config.Config, deadLetterHook service.DeadLetterHook, logger *slog.Logger, tracer *tracing.Tracer, auditLog *audit.Log) []service.Option {
+	opts := []service.Option{
+		service.WithLogger(logger),
+		service.WithTracer(tracer),
+		service.WithDefaultTypeConfig(typeConfig(cfg.Queue)),
+		service.WithDeadLetters(cfg.DeadLetters.Limit, deadLetterHook),
+		service.WithClientLimits(clientLimits(cfg)),
+	}
+	// A nil log must not become a non-nil Auditor.
+	if auditLog != nil {
+		opts = append(opts, service.WithAuditor(auditLog))
+	}
+
+	return opts
+}
+
+// typeConfig converts the configured limits of a task type into service limits.
+func typeConfig(c config.TypeConfig) service.TypeConfig {
+	return service.TypeConfig{
+		QueueSize:   c.QueueSize,
+		Concurrency: c.Concurrency,
+		Timeout:     c.Timeout.Std(),
+	}
+}
+
+// newDefaultTaskFactory returns a Factory for the "default" task type
+// drawing a delay within the configured range for every task.
+func newDefaultTaskFactory(cfg config.DefaultTaskConfig, clk clock.Clock) task.Factory {
+	return &task.DefaultTaskFactory{
+		MinDelay:    cfg.MinDelay.Std(),
+		MaxDelay:    cfg.MaxDelay.Std(),
+		FailureRate: cfg.FailureRate,
+		Seed:        cfg.Seed,
+		Clock:       clk,
+	}
+}
+
+// newExecTaskFactory returns a Factory for the "exec" task type
+// restricted to the configured commands, working directory, and environment.
+// The commands are resolved to absolute paths in the PATH of the configured environment.
+func newExecTaskFactory(cfg config.ExecTaskConfig) (task.Factory, error) {
+	f := &task.ExecTaskFactory{
+		AllowedCommands: cfg.AllowedCommands,
+		Dir:             cfg.Dir,
+		Env:             cfg.Env,
+		MaxOutputBytes:  cfg.MaxOutputBytes,
+	}
+	if err := f.Resolve(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
 
-	return &task.DefaultTaskFactory{Rng: rng, Delay: delay}
+// newHTTPTaskFactory returns a Factory for the "http" task type
+// restricted to the configured hosts.
+func newHTTPTaskFactory(cfg config.HTTPTaskConfig, clk clock.Clock) task.Factory {
+	return &task.HTTPTaskFactory{
+		AllowedHosts:  cfg.AllowedHosts,
+		AllowLoopback: cfg.AllowLoopback,
+		MaxBodyBytes:  cfg.MaxBodyBytes,
+		MaxRetries:    cfg.MaxRetries,
+		RetryBackoff:  cfg.RetryBackoff.Std(),
+		Clock:         clk,
+	}
 }
@@ -0,0 +1,101 @@
+package bootstrap_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/bootstrap"
+	"github.com/kylerqws/task-runner/internal/config"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+// loadConfig writes the JSON config to path and loads it.
+func loadConfig(t *testing.T, path, data string) *config.Config {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("cannot write config: %v", err)
+	}
+	cfg, err := config.Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return cfg
+}
+
+// newManager returns a task manager shut down at the end of the test.
+func newManager(t *testing.T) *service.TaskManager {
+	manager := service.NewTaskManager()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_ = manager.Shutdown(ctx)
+	})
+	return manager
+}
+
+// TestRegisterTaskFactories_Reload checks that registering again applies the new settings of the task types.
+func TestRegisterTaskFactories_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	load := func(commands string) *config.Config {
+		return loadConfig(t, path, `{"tasks": {"exec": {"disabled": false, "env": ["PATH=/usr/bin:/bin"], "allowed_commands": `+commands+`}}}`)
+	}
+
+	manager := newManager(t)
+	if err := bootstrap.RegisterTaskFactories(manager, load(`["true", "false"]`)); err != nil {
+		t.Fatalf("RegisterTaskFactories failed: %v", err)
+	}
+	h := router.InitTaskRouter(handler.NewTaskHandler(manager))
+
+	create := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"type":"exec","params":{"command":"false"}}`))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if status := create(); status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+
+	if err := bootstrap.RegisterTaskFactories(manager, load(`["true"]`)); err != nil {
+		t.Fatalf("RegisterTaskFactories failed: %v", err)
+	}
+
+	if status := create(); status != http.StatusBadRequest {
+		t.Errorf("expected 400 once the command is no longer allowed, got %d", status)
+	}
+}
+
+// TestRegisterTaskFactories_ReloadFailure checks that a reload failing on one type changes no type,
+// including the ones configured before it.
+func TestRegisterTaskFactories_ReloadFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	manager := newManager(t)
+
+	cfg := loadConfig(t, path, `{"tasks": {"default": {"queue_size": 10}, "exec": {"disabled": false, "env": ["PATH=/usr/bin:/bin"], "allowed_commands": ["true"]}}}`)
+	if err := bootstrap.RegisterTaskFactories(manager, cfg); err != nil {
+		t.Fatalf("RegisterTaskFactories failed: %v", err)
+	}
+
+	cfg = loadConfig(t, path, `{"tasks": {"default": {"queue_size": 20}, "exec": {"disabled": false, "env": ["PATH=/usr/bin:/bin"], "allowed_commands": ["no-such-command"]}}}`)
+	if err := bootstrap.RegisterTaskFactories(manager, cfg); err == nil {
+		t.Fatal("expected the unresolved command to fail the reload")
+	}
+
+	for _, tt := range manager.ListTaskTypes() {
+		if tt.Type == "default" && (tt.QueueSize != 10 || tt.Disabled) {
+			t.Errorf("expected the default type to keep its queue size of 10, got %+v", tt)
+		}
+	}
+	if _, err := manager.CreateTaskWithParams("exec", json.RawMessage(`{"command":"true"}`)); err != nil {
+		t.Errorf("expected the exec type to keep its allowed commands, got %v", err)
+	}
+}
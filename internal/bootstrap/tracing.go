@@ -0,0 +1,26 @@
+package bootstrap
+
+import (
+	"io"
+
+	"github.com/kylerqws/task-runner/internal/config"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// NewTracer creates the tracer of requests and tasks from the configuration, exporting spans
+// to the OTLP collector or as JSON lines to stdout. It returns nil if tracing is disabled.
+func NewTracer(cfg config.TracingConfig, stdout io.Writer) *tracing.Tracer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Exporter == config.TracingExporterStdout {
+		return tracing.NewTracer(tracing.NewWriterExporter(stdout))
+	}
+
+	return tracing.NewTracer(tracing.NewOTLPExporter(cfg.Endpoint,
+		tracing.WithServiceName(cfg.ServiceName),
+		tracing.WithHeaders(cfg.Headers),
+		tracing.WithFlushInterval(cfg.FlushInterval.Std()),
+	))
+}
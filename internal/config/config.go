@@ -0,0 +1,482 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Config holds all runtime settings of the application.
+// The config file is JSON only; YAML files are rejected by Load.
+type Config struct {
+	Server      ServerConfig     `json:"server"`       // HTTP server settings
+	Queue       TypeConfig       `json:"queue"`        // Defaults applied to every task type
+	Tasks       TasksConfig      `json:"tasks"`        // Per-type task settings
+	DeadLetters DeadLetterConfig `json:"dead_letters"` // Storage of failed tasks
+	Auth        AuthConfig       `json:"auth"`         // Authentication of API clients
+	Clients     ClientsConfig    `json:"clients"`      // Limits of authenticated clients
+	Log         LogConfig        `json:"log"`          // Application logs
+	Tracing     TracingConfig    `json:"tracing"`      // Tracing of requests and tasks
+	Audit       AuditConfig      `json:"audit"`        // Audit log of state-changing task operations
+
+	Path        string `json:"-"` // Path of the loaded config file (if any)
+	PrintConfig bool   `json:"-"` // Print the resolved config and exit
+}
+
+// ServerConfig holds HTTP server settings.
+type ServerConfig struct {
+	Addr              string           `json:"addr"`                // Listen address
+	ShutdownTimeout   Duration         `json:"shutdown_timeout"`    // Graceful shutdown timeout
+	ReadHeaderTimeout Duration         `json:"read_header_timeout"` // Max time to read the request headers
+	IdleTimeout       Duration         `json:"idle_timeout"`        // Max time to keep an idle connection open
+	MaxHeaderBytes    int              `json:"max_header_bytes"`    // Max size of the request headers
+	MaxBodyBytes      int              `json:"max_body_bytes"`      // Max size of a request body
+	TLS               TLSConfig        `json:"tls"`                 // HTTPS settings (plain HTTP without a certificate)
+	Middleware        MiddlewareConfig `json:"middleware"`          // Middleware wrapping every request
+}
+
+// MiddlewareConfig toggles the middleware wrapping every HTTP request.
+type MiddlewareConfig struct {
+	RequestID bool       `json:"request_id"` // Assign every request an X-Request-ID and echo it
+	AccessLog bool       `json:"access_log"` // Log every request with its status and duration
+	Recovery  bool       `json:"recovery"`   // Turn handler panics into 500 responses
+	Gzip      bool       `json:"gzip"`       // Compress responses for clients that accept gzip
+	CORS      CORSConfig `json:"cors"`       // Cross-origin requests from browsers
+}
+
+// CORSConfig holds the cross-origin requests allowed from browsers.
+type CORSConfig struct {
+	Enabled          bool     `json:"enabled"`           // Send CORS headers and answer preflight requests
+	AllowedOrigins   []string `json:"allowed_origins"`   // Origins allowed to call the API ("*" allows any)
+	AllowedMethods   []string `json:"allowed_methods"`   // Allowed methods (defaults to GET, POST, DELETE)
+	AllowedHeaders   []string `json:"allowed_headers"`   // Allowed request headers (defaults to the ones the API reads)
+	AllowCredentials bool     `json:"allow_credentials"` // Allow cookies and credentials
+	MaxAge           Duration `json:"max_age"`           // How long browsers may cache a preflight response
+}
+
+// TLSConfig holds the HTTPS settings of the server.
+type TLSConfig struct {
+	CertFile     string `json:"cert_file"`      // PEM certificate chain, reloaded when it changes
+	KeyFile      string `json:"key_file"`       // PEM private key, reloaded when it changes
+	ClientCAFile string `json:"client_ca_file"` // PEM CA bundle verifying client certificates (enables mTLS)
+	ClientAuth   string `json:"client_auth"`    // "require" (default with a client CA) or "optional"
+}
+
+// Client authentication modes of TLSConfig.ClientAuth.
+const (
+	ClientAuthRequire  = "require"
+	ClientAuthOptional = "optional"
+)
+
+// LogConfig holds the settings of the application logs, written to stderr.
+type LogConfig struct {
+	Format string `json:"format"` // "text" (default) or "json"
+	Level  string `json:"level"`  // Min level logged: "debug", "info" (default), "warn", or "error"
+}
+
+// Formats of LogConfig.Format.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// TracingConfig holds the settings of the spans recorded for HTTP requests and task runs.
+type TracingConfig struct {
+	Enabled       bool              `json:"enabled"`        // Record and export spans
+	Exporter      string            `json:"exporter"`       // "otlp" (default) or "stdout"
+	Endpoint      string            `json:"endpoint"`       // OTLP/HTTP traces endpoint of the collector
+	Headers       map[string]string `json:"headers"`        // Headers sent to the collector, e.g. credentials
+	ServiceName   string            `json:"service_name"`   // Value of the service.name resource attribute
+	FlushInterval Duration          `json:"flush_interval"` // How often buffered spans are sent to the collector
+}
+
+// Exporters of TracingConfig.Exporter.
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+)
+
+// DeadLetterConfig holds the settings of the dead letters, where failed tasks are moved.
+type DeadLetterConfig struct {
+	Limit      int    `json:"limit"`       // Max dead letters per task type (0 keeps failed tasks in the task list)
+	ExportPath string `json:"export_path"` // JSONL file every new dead letter is appended to (optional)
+}
+
+// AuditConfig holds the settings of the audit log, where every state-changing task operation is recorded.
+type AuditConfig struct {
+	Path     string `json:"path"`      // JSONL file the entries are appended to (empty disables the audit log)
+	MaxSize  int64  `json:"max_size"`  // Size in bytes beyond which the file is rotated (0 disables rotation)
+	MaxFiles int    `json:"max_files"` // Max rotated files kept, the oldest are removed first (10 by default, 0 keeps all)
+	Sync     bool   `json:"sync"`      // Sync the file to disk after every write
+}
+
+// AuthConfig holds the credentials accepted from API clients.
+// Authentication is disabled by default, in which case every client may use every endpoint.
+type AuthConfig struct {
+	Enabled     bool               `json:"enabled"`      // Require credentials on every endpoint except /openapi.json
+	APIKeys     []APIKeyConfig     `json:"api_keys"`     // Static API keys
+	JWT         JWTConfig          `json:"jwt"`          // JWT verification (optional)
+	ClientCerts []ClientCertConfig `json:"client_certs"` // Identities of TLS client certificates (optional)
+}
+
+// ClientCertConfig maps the subject of a verified TLS client certificate to a client.
+type ClientCertConfig struct {
+	Subject string   `json:"subject"` // Common name or full distinguished name of the certificate
+	Name    string   `json:"name"`    // Client name, recorded as the owner of its tasks
+	Scopes  []string `json:"scopes"`  // Granted scopes
+}
+
+// APIKeyConfig holds a static API key, stored as a hash.
+type APIKeyConfig struct {
+	Name   string   `json:"name"`   // Client name, recorded as the owner of its tasks
+	Hash   string   `json:"hash"`   // "sha256:" followed by the hex SHA-256 digest of the key
+	Scopes []string `json:"scopes"` // Granted scopes
+}
+
+// JWTConfig holds the settings of JWT verification.
+type JWTConfig struct {
+	JWKSFile string `json:"jwks_file"` // Local JWKS file with the public keys (empty disables JWTs)
+	Issuer   string `json:"issuer"`    // Required "iss" claim (optional)
+	Audience string `json:"audience"`  // Required "aud" claim (optional)
+}
+
+// ClientsConfig holds the limits of authenticated clients, identified by their API key name or JWT subject.
+type ClientsConfig struct {
+	Defaults  ClientLimitsConfig            `json:"defaults"`  // Limits of every client
+	Overrides map[string]ClientLimitsConfig `json:"overrides"` // Client name -> limits; zero values are inherited
+}
+
+// ClientLimitsConfig holds the limits of a client. Zero values mean no limit.
+type ClientLimitsConfig struct {
+	Rate       float64 `json:"rate"`        // Tasks created per second
+	Burst      int     `json:"burst"`       // Max tasks created at once (defaults to the rate rounded up)
+	MaxPending int     `json:"max_pending"` // Max pending tasks across all types
+	Weight     int     `json:"weight"`      // Share of the workers when clients compete (default 1)
+}
+
+// TypeConfig holds queue and execution limits of a single task type.
+// Zero values in per-type sections are inherited from the "queue" section.
+type TypeConfig struct {
+	Disabled    bool     `json:"disabled,omitempty"` // Reject new tasks of this type
+	QueueSize   int      `json:"queue_size"`         // Max number of pending and running tasks
+	Concurrency int      `json:"concurrency"`        // Max number of tasks running at once
+	Timeout     Duration `json:"timeout"`            // Max execution time per task (0 means no limit)
+}
+
+// TasksConfig holds the settings of every built-in task type.
+type TasksConfig struct {
+	Default DefaultTaskConfig `json:"default"` // "default" task type
+	Exec    ExecTaskConfig    `json:"exec"`    // "exec" task type
+	HTTP    HTTPTaskConfig    `json:"http"`    // "http" task type
+}
+
+// DefaultTaskConfig holds the settings of the "default" task type.
+type DefaultTaskConfig struct {
+	TypeConfig
+
+	Profile     string   `json:"profile,omitempty"` // Preset of delays ("realistic" or "simulate")
+	MinDelay    Duration `json:"min_delay"`         // Lower bound of the simulated delay
+	MaxDelay    Duration `json:"max_delay"`         // Upper bound of the simulated delay
+	FailureRate float64  `json:"failure_rate"`      // Probability of a simulated failure (0..1)
+	Seed        int64    `json:"seed"`              // Seed of the random generator (0 means time-based)
+}
+
+// ExecTaskConfig holds the settings of the "exec" task type.
+type ExecTaskConfig struct {
+	TypeConfig
+
+	AllowedCommands []string `json:"allowed_commands"` // Command names tasks may run
+	Dir             string   `json:"dir"`              // Working directory of the commands
+	Env             []string `json:"env"`              // Environment of the commands ("KEY=value")
+	MaxOutputBytes  int      `json:"max_output_bytes"` // Max captured bytes per output stream
+}
+
+// HTTPTaskConfig holds the settings of the "http" task type.
+type HTTPTaskConfig struct {
+	TypeConfig
+
+	AllowedHosts  []string `json:"allowed_hosts"`  // Host names tasks may call ("*.example.com" matches subdomains)
+	AllowLoopback bool     `json:"allow_loopback"` // Allow connections to loopback addresses (link-local and metadata ones never are)
+	MaxBodyBytes  int      `json:"max_body_bytes"` // Max stored response body bytes
+	MaxRetries    int      `json:"max_retries"`    // Max retries on 5xx responses (at most 100)
+	RetryBackoff  Duration `json:"retry_backoff"`  // Delay before the first retry, doubled on each retry
+}
+
+// Default returns the configuration used when nothing else is provided.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:              ":8080",
+			ShutdownTimeout:   Duration(5 * time.Second),
+			ReadHeaderTimeout: Duration(10 * time.Second),
+			IdleTimeout:       Duration(2 * time.Minute),
+			MaxHeaderBytes:    64 * 1024,
+			MaxBodyBytes:      4 * 1024 * 1024,
+			Middleware: MiddlewareConfig{
+				RequestID: true,
+				AccessLog: true,
+				Recovery:  true,
+			},
+		},
+		Queue: TypeConfig{
+			QueueSize:   100,
+			Concurrency: 1,
+		},
+		DeadLetters: DeadLetterConfig{
+			Limit: 1000,
+		},
+		Log: LogConfig{
+			Format: LogFormatText,
+			Level:  "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:      TracingExporterOTLP,
+			Endpoint:      "http://localhost:4318/v1/traces",
+			ServiceName:   "task-runner",
+			FlushInterval: Duration(5 * time.Second),
+		},
+		Audit: AuditConfig{
+			MaxSize:  64 * 1024 * 1024,
+			MaxFiles: 10,
+			Sync:     true,
+		},
+		Tasks: TasksConfig{
+			Default: DefaultTaskConfig{
+				MinDelay:    Duration(3 * time.Minute),
+				MaxDelay:    Duration(5 * time.Minute),
+				FailureRate: 0.4,
+			},
+			Exec: ExecTaskConfig{
+				TypeConfig:     TypeConfig{Disabled: true},
+				MaxOutputBytes: 64 * 1024,
+			},
+			HTTP: HTTPTaskConfig{
+				TypeConfig:   TypeConfig{Disabled: true},
+				MaxBodyBytes: 64 * 1024,
+				MaxRetries:   3,
+				RetryBackoff: Duration(time.Second),
+			},
+		},
+	}
+}
+
+// Resolve returns the type config with zero values inherited from the given defaults.
+func (c TypeConfig) Resolve(defaults TypeConfig) TypeConfig {
+	if c.QueueSize == 0 {
+		c.QueueSize = defaults.QueueSize
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = defaults.Concurrency
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaults.Timeout
+	}
+
+	return c
+}
+
+// resolve fills zero per-type limits with the values of the "queue" section.
+func (c *Config) resolve() {
+	c.Tasks.Default.TypeConfig = c.Tasks.Default.Resolve(c.Queue)
+	c.Tasks.Exec.TypeConfig = c.Tasks.Exec.Resolve(c.Queue)
+	c.Tasks.HTTP.TypeConfig = c.Tasks.HTTP.Resolve(c.Queue)
+}
+
+// Validate checks that the configuration is consistent and usable.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr must not be empty"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+	if c.Server.ReadHeaderTimeout <= 0 {
+		errs = append(errs, errors.New("server.read_header_timeout must be positive"))
+	}
+	if c.Server.IdleTimeout < 0 {
+		errs = append(errs, errors.New("server.idle_timeout must not be negative"))
+	}
+	if c.Server.MaxHeaderBytes < 1 {
+		errs = append(errs, errors.New("server.max_header_bytes must be at least 1"))
+	}
+	if c.Server.MaxBodyBytes < 1 {
+		errs = append(errs, errors.New("server.max_body_bytes must be at least 1"))
+	}
+
+	errs = append(errs, validateTLS(c.Server.TLS)...)
+	errs = append(errs, validateCORS(c.Server.Middleware.CORS)...)
+
+	errs = append(errs, validateType("queue", c.Queue)...)
+
+	errs = append(errs, validateLog(c.Log)...)
+	errs = append(errs, validateTracing(c.Tracing)...)
+
+	if c.DeadLetters.Limit < 0 {
+		errs = append(errs, errors.New("dead_letters.limit must not be negative"))
+	}
+	if c.Audit.MaxSize < 0 {
+		errs = append(errs, errors.New("audit.max_size must not be negative"))
+	}
+	if c.Audit.MaxFiles < 0 {
+		errs = append(errs, errors.New("audit.max_files must not be negative"))
+	}
+
+	if c.Auth.Enabled && len(c.Auth.APIKeys) == 0 && c.Auth.JWT.JWKSFile == "" && len(c.Auth.ClientCerts) == 0 {
+		errs = append(errs, errors.New("auth.api_keys, auth.jwt.jwks_file, or auth.client_certs must be set when auth is enabled"))
+	}
+	if len(c.Auth.ClientCerts) > 0 && c.Server.TLS.ClientCAFile == "" {
+		errs = append(errs, errors.New("auth.client_certs require server.tls.client_ca_file"))
+	}
+	for i, cc := range c.Auth.ClientCerts {
+		if cc.Subject == "" || cc.Name == "" {
+			errs = append(errs, fmt.Errorf("auth.client_certs[%d] must have a subject and a name", i))
+		}
+	}
+	errs = append(errs, validateClient("clients.defaults", c.Clients.Defaults)...)
+	for name, l := range c.Clients.Overrides {
+		errs = append(errs, validateClient("clients.overrides."+name, l)...)
+	}
+
+	for i, k := range c.Auth.APIKeys {
+		if k.Name == "" || k.Hash == "" {
+			errs = append(errs, fmt.Errorf("auth.api_keys[%d] must have a name and a hash", i))
+		}
+	}
+
+	errs = append(errs, validateType("tasks.default", c.Tasks.Default.TypeConfig)...)
+
+	if c.Tasks.Default.MinDelay < 0 {
+		errs = append(errs, errors.New("tasks.default.min_delay must not be negative"))
+	}
+	if c.Tasks.Default.MaxDelay < c.Tasks.Default.MinDelay {
+		errs = append(errs, errors.New("tasks.default.max_delay must not be less than min_delay"))
+	}
+	if c.Tasks.Default.FailureRate < 0 || c.Tasks.Default.FailureRate > 1 {
+		errs = append(errs, errors.New("tasks.default.failure_rate must be between 0 and 1"))
+	}
+
+	errs = append(errs, validateType("tasks.exec", c.Tasks.Exec.TypeConfig)...)
+
+	if !c.Tasks.Exec.Disabled && len(c.Tasks.Exec.AllowedCommands) == 0 {
+		errs = append(errs, errors.New("tasks.exec.allowed_commands must not be empty when the type is enabled"))
+	}
+	if c.Tasks.Exec.MaxOutputBytes < 1 {
+		errs = append(errs, errors.New("tasks.exec.max_output_bytes must be at least 1"))
+	}
+
+	errs = append(errs, validateType("tasks.http", c.Tasks.HTTP.TypeConfig)...)
+
+	if !c.Tasks.HTTP.Disabled && len(c.Tasks.HTTP.AllowedHosts) == 0 {
+		errs = append(errs, errors.New("tasks.http.allowed_hosts must not be empty when the type is enabled"))
+	}
+	if c.Tasks.HTTP.MaxBodyBytes < 1 {
+		errs = append(errs, errors.New("tasks.http.max_body_bytes must be at least 1"))
+	}
+	if c.Tasks.HTTP.MaxRetries < 0 || c.Tasks.HTTP.MaxRetries > 100 {
+		errs = append(errs, errors.New("tasks.http.max_retries must be between 0 and 100"))
+	}
+	if c.Tasks.HTTP.RetryBackoff <= 0 {
+		errs = append(errs, errors.New("tasks.http.retry_backoff must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTLS checks that the HTTPS settings are complete.
+func validateTLS(c TLSConfig) []error {
+	var errs []error
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs = append(errs, errors.New("server.tls.cert_file and key_file must be set together"))
+	}
+	if c.ClientCAFile != "" && c.CertFile == "" {
+		errs = append(errs, errors.New("server.tls.client_ca_file requires cert_file and key_file"))
+	}
+	if c.ClientAuth != "" && c.ClientAuth != ClientAuthRequire && c.ClientAuth != ClientAuthOptional {
+		errs = append(errs, fmt.Errorf("server.tls.client_auth must be %q or %q", ClientAuthRequire, ClientAuthOptional))
+	}
+
+	return errs
+}
+
+// validateLog checks the format and level of the logs.
+func validateLog(c LogConfig) []error {
+	var errs []error
+
+	if c.Format != LogFormatText && c.Format != LogFormatJSON {
+		errs = append(errs, fmt.Errorf("log.format must be %q or %q", LogFormatText, LogFormatJSON))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		errs = append(errs, errors.New("log.level must be \"debug\", \"info\", \"warn\", or \"error\""))
+	}
+
+	return errs
+}
+
+// validateTracing checks the exporter of the spans.
+func validateTracing(c TracingConfig) []error {
+	var errs []error
+
+	if c.Exporter != TracingExporterOTLP && c.Exporter != TracingExporterStdout {
+		errs = append(errs, fmt.Errorf("tracing.exporter must be %q or %q", TracingExporterOTLP, TracingExporterStdout))
+	}
+	if c.Exporter == TracingExporterOTLP {
+		if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, errors.New("tracing.endpoint must be an http or https URL"))
+		}
+	}
+	if c.FlushInterval <= 0 {
+		errs = append(errs, errors.New("tracing.flush_interval must be positive"))
+	}
+
+	return errs
+}
+
+// validateCORS checks that enabled CORS allows some origin, and credentials only from listed ones.
+func validateCORS(c CORSConfig) []error {
+	var errs []error
+
+	if c.Enabled && len(c.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("server.middleware.cors.allowed_origins must not be empty when CORS is enabled"))
+	}
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		errs = append(errs, errors.New("server.middleware.cors.allow_credentials cannot be used with the \"*\" origin"))
+	}
+	if c.MaxAge < 0 {
+		errs = append(errs, errors.New("server.middleware.cors.max_age must not be negative"))
+	}
+
+	return errs
+}
+
+// validateClient checks the limits of a single client section.
+func validateClient(section string, c ClientLimitsConfig) []error {
+	if c.Rate < 0 || c.Burst < 0 || c.MaxPending < 0 || c.Weight < 0 {
+		return []error{fmt.Errorf("%s limits must not be negative", section)}
+	}
+
+	return nil
+}
+
+// validateType checks the limits of a single task type section.
+func validateType(section string, c TypeConfig) []error {
+	var errs []error
+
+	if c.QueueSize < 1 {
+		errs = append(errs, fmt.Errorf("%s.queue_size must be at least 1", section))
+	}
+	if c.Concurrency < 1 {
+		errs = append(errs, fmt.Errorf("%s.concurrency must be at least 1", section))
+	}
+	if c.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("%s.timeout must not be negative", section))
+	}
+
+	return errs
+}
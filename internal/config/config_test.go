@@ -0,0 +1,136 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/config"
+)
+
+// writeConfig writes the given JSON into a temporary config file and returns its path.
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("cannot write config file: %v", err)
+	}
+	return path
+}
+
+// TestLoad_Defaults checks that loading without sources yields the built-in defaults.
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Server.Addr != ":8080" {
+		t.Errorf("expected addr ':8080', got %q", cfg.Server.Addr)
+	}
+	if cfg.Queue.QueueSize != 100 {
+		t.Errorf("expected queue size 100, got %d", cfg.Queue.QueueSize)
+	}
+	if cfg.Server.ShutdownTimeout.Std() != 5*time.Second {
+		t.Errorf("expected shutdown timeout 5s, got %v", cfg.Server.ShutdownTimeout)
+	}
+}
+
+// TestLoad_Precedence checks that env vars override the file and flags override env vars.
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfig(t, `{
+		"server": {"addr": ":9000"},
+		"queue": {"queue_size": 10, "concurrency": 2},
+		"tasks": {"default": {"timeout": "1m", "min_delay": "1s", "max_delay": "2s"}}
+	}`)
+	t.Setenv("TASK_RUNNER_QUEUE_SIZE", "20")
+	t.Setenv("TASK_RUNNER_ADDR", ":9001")
+
+	cfg, err := config.Load([]string{"--config", path, "--addr", ":9002"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Server.Addr != ":9002" {
+		t.Errorf("expected flag addr ':9002', got %q", cfg.Server.Addr)
+	}
+	if cfg.Queue.QueueSize != 20 {
+		t.Errorf("expected env queue size 20, got %d", cfg.Queue.QueueSize)
+	}
+	if cfg.Queue.Concurrency != 2 {
+		t.Errorf("expected file concurrency 2, got %d", cfg.Queue.Concurrency)
+	}
+
+	def := cfg.Tasks.Default.TypeConfig.Resolve(cfg.Queue)
+	if def.Timeout.Std() != time.Minute || def.QueueSize != 20 {
+		t.Errorf("unexpected resolved default type config: %+v", def)
+	}
+}
+
+// TestLoad_Invalid checks that invalid values and unknown fields are rejected.
+func TestLoad_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"negative concurrency":     `{"queue": {"concurrency": -1}}`,
+		"inverted delays":          `{"tasks": {"default": {"min_delay": "2m", "max_delay": "1m"}}}`,
+		"unknown field":            `{"queue": {"size": 1}}`,
+		"bad duration":             `{"server": {"shutdown_timeout": "soon"}}`,
+		"bad failure rate":         `{"tasks": {"default": {"failure_rate": 2}}}`,
+		"negative dead letters":    `{"dead_letters": {"limit": -1}}`,
+		"auth without credentials": `{"auth": {"enabled": true}}`,
+		"api key without hash":     `{"auth": {"api_keys": [{"name": "ci"}]}}`,
+		"tls cert without key":     `{"server": {"tls": {"cert_file": "cert.pem"}}}`,
+		"client certs without CA":  `{"auth": {"client_certs": [{"subject": "ci", "name": "ci"}]}}`,
+		"negative client rate":     `{"clients": {"overrides": {"ci": {"rate": -1}}}}`,
+		"unknown log format":       `{"log": {"format": "xml"}}`,
+		"unknown log level":        `{"log": {"level": "loud"}}`,
+		"unknown span exporter":    `{"tracing": {"exporter": "zipkin"}}`,
+		"bad otlp endpoint":        `{"tracing": {"endpoint": "localhost:4318"}}`,
+		"negative audit size":      `{"audit": {"max_size": -1}}`,
+		"too many http retries":    `{"tasks": {"http": {"max_retries": 1000}}}`,
+		"cors without origins":     `{"server": {"middleware": {"cors": {"enabled": true}}}}`,
+		"cors credentials for any": `{"server": {"middleware": {"cors": {"allowed_origins": ["*"], "allow_credentials": true}}}}`,
+	}
+
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := config.Load([]string{"--config", writeConfig(t, data)}); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+// TestLoad_YAML checks that YAML config files are rejected with an explicit error.
+func TestLoad_YAML(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.YML"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte("server:\n  addr: \":9000\"\n"), 0o600); err != nil {
+			t.Fatalf("cannot write config file: %v", err)
+		}
+
+		_, err := config.Load([]string{"--config", path})
+		if err == nil || !strings.Contains(err.Error(), "YAML is not supported") {
+			t.Errorf("expected YAML to be rejected for %s, got %v", name, err)
+		}
+	}
+}
+
+// TestLoad_Profile checks that a profile changes the base values but explicit values still win.
+func TestLoad_Profile(t *testing.T) {
+	path := writeConfig(t, `{"tasks": {"default": {"profile": "simulate", "max_delay": "10s"}}}`)
+
+	cfg, err := config.Load([]string{"--config", path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Tasks.Default.MinDelay.Std() != time.Second {
+		t.Errorf("expected profile min delay 1s, got %v", cfg.Tasks.Default.MinDelay)
+	}
+	if cfg.Tasks.Default.MaxDelay.Std() != 10*time.Second {
+		t.Errorf("expected explicit max delay 10s, got %v", cfg.Tasks.Default.MaxDelay)
+	}
+
+	if _, err := config.Load([]string{"--profile", "unknown"}); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration wraps time.Duration to read and write human-readable values (e.g. "5s", "3m").
+type Duration time.Duration
+
+// Std returns the value as a standard time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// String returns the duration in time.Duration notation.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Set parses the duration from a string. It allows Duration to be used as a flag value.
+func (d *Duration) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes the duration from a string or from a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	switch val := v.(type) {
+	case string:
+		if err := d.Set(val); err != nil {
+			return fmt.Errorf("invalid duration %q: %w", val, err)
+		}
+		return nil
+	case float64:
+		*d = Duration(val)
+		return nil
+	default:
+		return fmt.Errorf("invalid duration %s", b)
+	}
+}
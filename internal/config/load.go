@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// envPrefix is prepended to every environment variable read by the loader.
+const envPrefix = "TASK_RUNNER_"
+
+// Load builds the configuration from defaults, the config file, environment variables,
+// and command-line flags, each overriding the previous one. The result is validated.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("task-runner", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	flags := &Config{}
+	fs.StringVar(&flags.Path, "config", os.Getenv(envPrefix+"CONFIG"), "path to a JSON config file")
+	fs.BoolVar(&flags.PrintConfig, "print-config", false, "print the resolved config and exit")
+	fs.StringVar(&flags.Server.Addr, "addr", "", "HTTP listen address")
+	fs.Var(&flags.Server.ShutdownTimeout, "shutdown-timeout", "graceful shutdown timeout")
+	fs.StringVar(&flags.Server.TLS.CertFile, "tls-cert", "", "PEM certificate file of the server")
+	fs.StringVar(&flags.Server.TLS.KeyFile, "tls-key", "", "PEM private key file of the server")
+	fs.IntVar(&flags.Queue.QueueSize, "queue-size", 0, "default queue size per task type")
+	fs.IntVar(&flags.Queue.Concurrency, "concurrency", 0, "default concurrency per task type")
+	fs.Var(&flags.Queue.Timeout, "timeout", "default execution timeout per task")
+	fs.StringVar(&flags.Tasks.Default.Profile, "profile", "", "profile of the default task type (realistic, simulate)")
+	fs.StringVar(&flags.Log.Format, "log-format", "", "log format (text, json)")
+	fs.StringVar(&flags.Log.Level, "log-level", "", "min log level (debug, info, warn, error)")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	cfg, err := build(Default(), fs, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	// A profile only changes the base values, so sources are applied again on top of it.
+	if profile := cfg.Tasks.Default.Profile; profile != "" {
+		base := Default()
+		if err := applyProfile(base, profile); err != nil {
+			return nil, err
+		}
+		if cfg, err = build(base, fs, flags); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.resolve()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// build applies the config file, environment variables, and parsed flags on top of the base config.
+func build(cfg *Config, fs *flag.FlagSet, flags *Config) (*Config, error) {
+	cfg.Path = flags.Path
+	cfg.PrintConfig = flags.PrintConfig
+
+	if cfg.Path != "" {
+		if err := loadFile(cfg, cfg.Path); err != nil {
+			return nil, err
+		}
+	}
+	if err := loadEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.Server.Addr = flags.Server.Addr
+		case "shutdown-timeout":
+			cfg.Server.ShutdownTimeout = flags.Server.ShutdownTimeout
+		case "tls-cert":
+			cfg.Server.TLS.CertFile = flags.Server.TLS.CertFile
+		case "tls-key":
+			cfg.Server.TLS.KeyFile = flags.Server.TLS.KeyFile
+		case "queue-size":
+			cfg.Queue.QueueSize = flags.Queue.QueueSize
+		case "concurrency":
+			cfg.Queue.Concurrency = flags.Queue.Concurrency
+		case "timeout":
+			cfg.Queue.Timeout = flags.Queue.Timeout
+		case "profile":
+			cfg.Tasks.Default.Profile = flags.Tasks.Default.Profile
+		case "log-format":
+			cfg.Log.Format = flags.Log.Format
+		case "log-level":
+			cfg.Log.Level = flags.Log.Level
+		}
+	})
+
+	return cfg, nil
+}
+
+// loadFile decodes the JSON config file on top of the current values.
+// Unknown fields are rejected to catch typos early. Only JSON is supported: a path with
+// a YAML extension is rejected with a clear error rather than failing on the first character.
+func loadFile(cfg *Config, path string) error {
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		return fmt.Errorf("cannot load config file %q: YAML is not supported, use JSON", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read config file %q: %w", path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("cannot decode config file %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// loadEnv overrides config values with the environment variables that are set.
+func loadEnv(cfg *Config) error {
+	vars := []struct {
+		name string
+		set  func(string) error
+	}{
+		{"ADDR", setString(&cfg.Server.Addr)},
+		{"SHUTDOWN_TIMEOUT", cfg.Server.ShutdownTimeout.Set},
+		{"READ_HEADER_TIMEOUT", cfg.Server.ReadHeaderTimeout.Set},
+		{"IDLE_TIMEOUT", cfg.Server.IdleTimeout.Set},
+		{"MAX_HEADER_BYTES", setInt(&cfg.Server.MaxHeaderBytes)},
+		{"MAX_BODY_BYTES", setInt(&cfg.Server.MaxBodyBytes)},
+		{"TLS_CERT_FILE", setString(&cfg.Server.TLS.CertFile)},
+		{"TLS_KEY_FILE", setString(&cfg.Server.TLS.KeyFile)},
+		{"TLS_CLIENT_CA_FILE", setString(&cfg.Server.TLS.ClientCAFile)},
+		{"TLS_CLIENT_AUTH", setString(&cfg.Server.TLS.ClientAuth)},
+		{"REQUEST_ID", setBool(&cfg.Server.Middleware.RequestID)},
+		{"ACCESS_LOG", setBool(&cfg.Server.Middleware.AccessLog)},
+		{"RECOVERY", setBool(&cfg.Server.Middleware.Recovery)},
+		{"GZIP", setBool(&cfg.Server.Middleware.Gzip)},
+		{"CORS_ENABLED", setBool(&cfg.Server.Middleware.CORS.Enabled)},
+		{"CORS_ALLOWED_ORIGINS", setList(&cfg.Server.Middleware.CORS.AllowedOrigins)},
+		{"QUEUE_SIZE", setInt(&cfg.Queue.QueueSize)},
+		{"CONCURRENCY", setInt(&cfg.Queue.Concurrency)},
+		{"TIMEOUT", cfg.Queue.Timeout.Set},
+		{"DEFAULT_DISABLED", setBool(&cfg.Tasks.Default.Disabled)},
+		{"DEFAULT_QUEUE_SIZE", setInt(&cfg.Tasks.Default.QueueSize)},
+		{"DEFAULT_CONCURRENCY", setInt(&cfg.Tasks.Default.Concurrency)},
+		{"DEFAULT_TIMEOUT", cfg.Tasks.Default.Timeout.Set},
+		{"DEFAULT_MIN_DELAY", cfg.Tasks.Default.MinDelay.Set},
+		{"DEFAULT_MAX_DELAY", cfg.Tasks.Default.MaxDelay.Set},
+		{"DEFAULT_FAILURE_RATE", setFloat(&cfg.Tasks.Default.FailureRate)},
+		{"DEFAULT_SEED", setInt64(&cfg.Tasks.Default.Seed)},
+		{"DEFAULT_PROFILE", setString(&cfg.Tasks.Default.Profile)},
+		{"EXEC_DISABLED", setBool(&cfg.Tasks.Exec.Disabled)},
+		{"EXEC_QUEUE_SIZE", setInt(&cfg.Tasks.Exec.QueueSize)},
+		{"EXEC_CONCURRENCY", setInt(&cfg.Tasks.Exec.Concurrency)},
+		{"EXEC_TIMEOUT", cfg.Tasks.Exec.Timeout.Set},
+		{"HTTP_DISABLED", setBool(&cfg.Tasks.HTTP.Disabled)},
+		{"HTTP_QUEUE_SIZE", setInt(&cfg.Tasks.HTTP.QueueSize)},
+		{"HTTP_CONCURRENCY", setInt(&cfg.Tasks.HTTP.Concurrency)},
+		{"HTTP_TIMEOUT", cfg.Tasks.HTTP.Timeout.Set},
+		{"DEAD_LETTER_LIMIT", setInt(&cfg.DeadLetters.Limit)},
+		{"DEAD_LETTER_EXPORT", setString(&cfg.DeadLetters.ExportPath)},
+		{"CLIENT_RATE", setFloat(&cfg.Clients.Defaults.Rate)},
+		{"CLIENT_BURST", setInt(&cfg.Clients.Defaults.Burst)},
+		{"CLIENT_MAX_PENDING", setInt(&cfg.Clients.Defaults.MaxPending)},
+		{"AUTH_ENABLED", setBool(&cfg.Auth.Enabled)},
+		{"AUTH_JWKS_FILE", setString(&cfg.Auth.JWT.JWKSFile)},
+		{"AUTH_JWT_ISSUER", setString(&cfg.Auth.JWT.Issuer)},
+		{"AUTH_JWT_AUDIENCE", setString(&cfg.Auth.JWT.Audience)},
+		{"LOG_FORMAT", setString(&cfg.Log.Format)},
+		{"LOG_LEVEL", setString(&cfg.Log.Level)},
+		{"TRACING_ENABLED", setBool(&cfg.Tracing.Enabled)},
+		{"TRACING_EXPORTER", setString(&cfg.Tracing.Exporter)},
+		{"TRACING_ENDPOINT", setString(&cfg.Tracing.Endpoint)},
+		{"TRACING_SERVICE_NAME", setString(&cfg.Tracing.ServiceName)},
+		{"AUDIT_PATH", setString(&cfg.Audit.Path)},
+		{"AUDIT_MAX_SIZE", setInt64(&cfg.Audit.MaxSize)},
+		{"AUDIT_MAX_FILES", setInt(&cfg.Audit.MaxFiles)},
+		{"AUDIT_SYNC", setBool(&cfg.Audit.Sync)},
+	}
+
+	for _, v := range vars {
+		val, ok := os.LookupEnv(envPrefix + v.name)
+		if !ok {
+			continue
+		}
+		if err := v.set(val); err != nil {
+			return fmt.Errorf("invalid value of %s%s: %w", envPrefix, v.name, err)
+		}
+	}
+
+	return nil
+}
+
+// setString returns a setter that assigns the raw value to dst.
+func setString(dst *string) func(string) error {
+	return func(s string) error {
+		*dst = s
+		return nil
+	}
+}
+
+// setList returns a setter that splits the comma-separated value into dst, ignoring empty items.
+func setList(dst *[]string) func(string) error {
+	return func(s string) error {
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		*dst = items
+		return nil
+	}
+}
+
+// setBool returns a setter that parses the value as a boolean into dst.
+func setBool(dst *bool) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		*dst = v
+		return nil
+	}
+}
+
+// setInt returns a setter that parses the value as an integer into dst.
+func setInt(dst *int) func(string) error {
+	return func(s string) error {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+
+		*dst = v
+		return nil
+	}
+}
+
+// setInt64 returns a setter that parses the value as a 64-bit integer into dst.
+func setInt64(dst *int64) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		*dst = v
+		return nil
+	}
+}
+
+// setFloat returns a setter that parses the value as a floating-point number into dst.
+func setFloat(dst *float64) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+
+		*dst = v
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Profiles of the "default" task type.
+const (
+	// ProfileRealistic keeps the built-in minutes-long delays.
+	ProfileRealistic = "realistic"
+
+	// ProfileSimulate uses second-long delays suitable for demos and integration tests.
+	ProfileSimulate = "simulate"
+)
+
+// applyProfile sets the preset values of the named profile on the base config.
+func applyProfile(cfg *Config, profile string) error {
+	switch profile {
+	case ProfileRealistic:
+	case ProfileSimulate:
+		cfg.Tasks.Default.MinDelay = Duration(time.Second)
+		cfg.Tasks.Default.MaxDelay = Duration(3 * time.Second)
+	default:
+		return fmt.Errorf("unknown profile %q", profile)
+	}
+
+	cfg.Tasks.Default.Profile = profile
+	return nil
+}
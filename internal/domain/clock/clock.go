@@ -0,0 +1,68 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the current time and timers.
+// It allows time-dependent code to be driven by a fake clock in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// NewTimer creates a timer that sends the current time on its channel after d.
+	NewTimer(d time.Duration) Timer
+
+	// AfterFunc calls f in its own goroutine after d.
+	AfterFunc(d time.Duration, f func()) Timer
+
+	// NewTicker creates a ticker that sends the current time on its channel every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event created by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered (nil for AfterFunc timers).
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing and reports whether it was active.
+	Stop() bool
+}
+
+// Ticker is a periodic event created by a Clock.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// WithTimeout returns a copy of ctx that is cancelled after d as measured by the clock.
+// The cause of the cancellation is context.DeadlineExceeded.
+func WithTimeout(ctx context.Context, c Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := c.AfterFunc(d, func() { cancel(context.DeadlineExceeded) })
+
+	return ctx, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
+// Sleep pauses until d has passed on the clock or ctx is done, and returns ctx.Err() in the latter case.
+func Sleep(ctx context.Context, c Clock, d time.Duration) error {
+	timer := c.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
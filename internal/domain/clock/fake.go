@@ -0,0 +1,182 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when Advance is called.
+// It is intended for tests and is safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeTicker adapts a periodic fakeWaiter to the Ticker interface.
+type fakeTicker struct{ *fakeWaiter }
+
+// fakeWaiter is a timer, ticker, or AfterFunc registered on a Fake clock.
+type fakeWaiter struct {
+	fake   *Fake
+	at     time.Time
+	period time.Duration // Non-zero for tickers
+	ch     chan time.Time
+	fn     func()
+}
+
+// NewFake returns a Fake clock set to the given time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+
+	return f
+}
+
+// Now returns the current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Since returns the fake time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// NewTimer creates a timer firing when the fake time reaches now+d.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.add(&fakeWaiter{fake: f, ch: make(chan time.Time, 1)}, d)
+}
+
+// AfterFunc calls fn in its own goroutine when the fake time reaches now+d.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.add(&fakeWaiter{fake: f, fn: fn}, d)
+}
+
+// NewTicker creates a ticker firing every d of fake time.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	return fakeTicker{f.add(&fakeWaiter{fake: f, period: d, ch: make(chan time.Time, 1)}, d)}
+}
+
+// Advance moves the fake time forward by d and fires every waiter that became due, in order.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := f.now.Add(d)
+
+	for {
+		sort.SliceStable(f.waiters, func(i, j int) bool { return f.waiters[i].at.Before(f.waiters[j].at) })
+		if len(f.waiters) == 0 || f.waiters[0].at.After(end) {
+			break
+		}
+
+		w := f.waiters[0]
+		f.now = w.at
+		f.fire(w)
+	}
+
+	f.now = end
+}
+
+// BlockUntil waits until at least n timers, tickers, or AfterFunc calls are pending.
+// It lets tests wait for goroutines to reach a point where they depend on the clock.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// Waiters returns the number of pending timers, tickers, and AfterFunc calls.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// add registers the waiter to fire after d.
+func (f *Fake) add(w *fakeWaiter, d time.Duration) *fakeWaiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.at = f.now.Add(d)
+	if d <= 0 && w.period == 0 {
+		f.fireNow(w)
+		return w
+	}
+
+	f.waiters = append(f.waiters, w)
+	f.cond.Broadcast()
+
+	return w
+}
+
+// fire delivers a due waiter and reschedules or removes it.
+// WARNING: Must be called with f.mu held.
+func (f *Fake) fire(w *fakeWaiter) {
+	if w.period > 0 {
+		w.at = w.at.Add(w.period)
+	} else {
+		f.remove(w)
+	}
+
+	f.fireNow(w)
+}
+
+// fireNow sends the current time on the waiter channel or starts its function.
+// WARNING: Must be called with f.mu held.
+func (f *Fake) fireNow(w *fakeWaiter) {
+	if w.fn != nil {
+		go w.fn()
+		return
+	}
+
+	select {
+	case w.ch <- f.now:
+	default:
+	}
+}
+
+// remove unregisters the waiter and reports whether it was pending.
+// WARNING: Must be called with f.mu held.
+func (f *Fake) remove(w *fakeWaiter) bool {
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// C returns the waiter channel.
+func (w *fakeWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+// Stop unregisters the waiter and reports whether it was pending.
+func (w *fakeWaiter) Stop() bool {
+	w.fake.mu.Lock()
+	defer w.fake.mu.Unlock()
+
+	return w.fake.remove(w)
+}
+
+// Stop turns off the ticker.
+func (t fakeTicker) Stop() {
+	t.fakeWaiter.Stop()
+}
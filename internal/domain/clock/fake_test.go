@@ -0,0 +1,56 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/clock"
+)
+
+// TestFake_Advance checks that timers fire only once the fake time reaches them.
+func TestFake_Advance(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	start := fake.Now()
+
+	timer := fake.NewTimer(time.Second)
+	fake.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired too early")
+	default:
+	}
+
+	fake.Advance(time.Millisecond)
+	select {
+	case at := <-timer.C():
+		if at.Sub(start) != time.Second {
+			t.Errorf("expected the timer to fire at +1s, got +%v", at.Sub(start))
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+
+	if fake.Since(start) != time.Second {
+		t.Errorf("expected 1s elapsed, got %v", fake.Since(start))
+	}
+	if fake.Waiters() != 0 {
+		t.Errorf("expected no pending waiters, got %d", fake.Waiters())
+	}
+}
+
+// TestWithTimeout checks that the context is cancelled with DeadlineExceeded once the fake time passes.
+func TestWithTimeout(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	ctx, cancel := clock.WithTimeout(context.Background(), fake, time.Minute)
+	defer cancel()
+
+	fake.Advance(time.Minute)
+	<-ctx.Done()
+
+	if !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded cause, got %v", context.Cause(ctx))
+	}
+}
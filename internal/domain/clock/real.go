@@ -0,0 +1,54 @@
+package clock
+
+import "time"
+
+type (
+	realClock  struct{}                    // realClock delegates to the time package.
+	realTimer  struct{ timer *time.Timer } // realTimer wraps a time.Timer.
+	realTicker struct{ *time.Ticker }      // realTicker wraps a time.Ticker.
+)
+
+// Real returns the Clock backed by the system time.
+func Real() Clock {
+	return realClock{}
+}
+
+// Now returns the current system time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t.
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// NewTimer creates a system timer.
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+// AfterFunc calls f in its own goroutine after d.
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{timer: time.AfterFunc(d, f)}
+}
+
+// NewTicker creates a system ticker.
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+// C returns the timer channel.
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Stop stops the timer.
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// C returns the ticker channel.
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
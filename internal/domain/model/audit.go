@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// AuditAction is a state-changing operation recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"  // Task created, alone or in a batch
+	AuditActionDelete  AuditAction = "delete"  // Task deleted, alone or by filter
+	AuditActionCancel  AuditAction = "cancel"  // Pending or running task canceled
+	AuditActionRetry   AuditAction = "retry"   // Finished task queued again, in place or as a new task
+	AuditActionRequeue AuditAction = "requeue" // Dead letter queued again
+	AuditActionDiscard AuditAction = "discard" // Dead letter removed for good
+	AuditActionPurge   AuditAction = "purge"   // Pending task removed with the rest of its queue
+	AuditActionPause   AuditAction = "pause"   // Queue paused
+	AuditActionResume  AuditAction = "resume"  // Queue resumed
+)
+
+// AuditEntry records who changed the state of a task or queue, and when.
+type AuditEntry struct {
+	Time      time.Time   `json:"time"`                 // When the operation was requested
+	Actor     string      `json:"actor"`                // Identity of the client that requested the operation
+	Action    AuditAction `json:"action"`               // Requested operation
+	TaskID    string      `json:"task_id,omitempty"`    // ID of the affected task (empty for queue operations)
+	TaskType  string      `json:"task_type"`            // Type of the affected task or queue
+	RetryOf   string      `json:"retry_of,omitempty"`   // ID of the task retried by the affected one
+	Before    TaskStatus  `json:"before,omitempty"`     // Status before the operation (empty if the task did not exist)
+	After     TaskStatus  `json:"after,omitempty"`      // Status right after the operation (empty if the task was removed)
+	RequestID string      `json:"request_id,omitempty"` // ID of the HTTP request that requested the operation
+}
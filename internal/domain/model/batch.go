@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// BatchMode defines how a batch of tasks is submitted.
+type BatchMode string
+
+const (
+	BatchModeAtomic     BatchMode = "atomic"      // All tasks are queued or none
+	BatchModeBestEffort BatchMode = "best_effort" // Each task is queued independently
+)
+
+// IsValid reports whether the mode is one of the known batch modes.
+func (m BatchMode) IsValid() bool {
+	return m == BatchModeAtomic || m == BatchModeBestEffort
+}
+
+// Batch holds the aggregate progress of tasks submitted together.
+type Batch struct {
+	ID        string     `json:"id"`              // Unique batch identifier
+	Mode      BatchMode  `json:"mode"`            // Submission mode
+	Owner     string     `json:"owner,omitempty"` // Identity of the client that submitted the batch
+	CreatedAt time.Time  `json:"created_at"`      // Batch submission timestamp
+	Total     int        `json:"total"`           // Number of tasks queued by the batch
+	Stats     BatchStats `json:"stats"`           // Task counters by status
+	Finished  bool       `json:"finished"`        // All remaining tasks reached a final status
+	TaskIDs   []string   `json:"task_ids"`        // IDs of the tasks queued by the batch
+}
+
+// BatchStats holds task counters of a batch by status.
+type BatchStats struct {
+	Pending  int `json:"pending"`  // Tasks waiting in the queue
+	Running  int `json:"running"`  // Tasks being executed
+	Done     int `json:"done"`     // Tasks completed successfully
+	Failed   int `json:"failed"`   // Tasks finished with an error
+	Canceled int `json:"canceled"` // Tasks canceled before completion
+	Deleted  int `json:"deleted"`  // Tasks deleted since submission
+}
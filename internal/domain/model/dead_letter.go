@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// DeadLetter is a failed task kept in the dead letters of its type with its error history.
+type DeadLetter struct {
+	Task   *Task       `json:"task"`    // Failed task as of its last run
+	DeadAt time.Time   `json:"dead_at"` // When the task was moved to the dead letters
+	Errors []TaskError `json:"errors"`  // Errors of every failed run, oldest first
+}
+
+// TaskError is the error of a failed run of a task.
+type TaskError struct {
+	Attempt  int       `json:"attempt"`   // Run number, starting at 1
+	FailedAt time.Time `json:"failed_at"` // When the run finished
+	Error    string    `json:"error"`     // Result message of the run
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Queue describes the queue of a task type with its paused state and live load.
+type Queue struct {
+	Type            string            `json:"type"`                 // Task type identifier
+	Paused          bool              `json:"paused"`               // Queued tasks are not started
+	PausedAt        *time.Time        `json:"paused_at,omitempty"`  // When the queue was paused (if paused)
+	RejectNew       bool              `json:"reject_new"`           // New tasks are rejected while paused
+	Depth           int               `json:"depth"`                // Tasks waiting in the queue
+	Active          int               `json:"active"`               // Pending and running tasks counted against the queue size
+	Running         int               `json:"running"`              // Tasks being executed
+	QueueSize       int               `json:"queue_size"`           // Max number of pending and running tasks
+	Concurrency     int               `json:"concurrency"`          // Max number of tasks running at once
+	OldestPendingMs int64             `json:"oldest_pending_ms"`    // Age of the oldest pending task, in milliseconds
+	DeadLetters     int               `json:"dead_letters"`         // Failed tasks kept in the dead letters
+	Throughput      []QueueThroughput `json:"throughput,omitempty"` // Tasks finished over recent windows
+}
+
+// QueueThroughput holds the number of tasks finished by the workers of a type over a recent window.
+type QueueThroughput struct {
+	Window   string `json:"window"`   // Window length (e.g. "1m")
+	Done     int    `json:"done"`     // Tasks completed successfully
+	Failed   int    `json:"failed"`   // Tasks finished with an error
+	Canceled int    `json:"canceled"` // Tasks canceled while running
+}
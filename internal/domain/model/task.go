@@ -1,28 +1,91 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
 
 // TaskStatus represents the current status of a task.
 type TaskStatus string
 
 const (
-	TaskStatusPending TaskStatus = "pending"
-	TaskStatusRunning TaskStatus = "running"
-	TaskStatusDone    TaskStatus = "done"
-	TaskStatusFailed  TaskStatus = "failed"
+	TaskStatusPending  TaskStatus = "pending"
+	TaskStatusRunning  TaskStatus = "running"
+	TaskStatusDone     TaskStatus = "done"
+	TaskStatusFailed   TaskStatus = "failed"
+	TaskStatusCanceled TaskStatus = "canceled"
 )
 
+// IsFinal reports whether the status is terminal and will not change anymore.
+func (s TaskStatus) IsFinal() bool {
+	return s == TaskStatusDone || s == TaskStatusFailed || s == TaskStatusCanceled
+}
+
+// IsValid reports whether the status is one of the known task statuses.
+func (s TaskStatus) IsValid() bool {
+	return s == TaskStatusPending || s == TaskStatusRunning || s.IsFinal()
+}
+
 // Task holds metadata about an asynchronous task's lifecycle and result.
 type Task struct {
-	ID        string     `json:"id"`                 // Unique task identifier
-	Type      string     `json:"type"`               // Type of the task (e.g. "default", etc.)
-	Status    TaskStatus `json:"status"`             // Current task status
-	CreatedAt time.Time  `json:"created_at"`         // Task creation timestamp
-	Duration  string     `json:"duration,omitempty"` // Total execution time (if available)
-	Result    string     `json:"result,omitempty"`   // Result message or error
+	ID               string          `json:"id"`                    // Unique task identifier
+	Type             string          `json:"type"`                  // Type of the task (e.g. "default", etc.)
+	Status           TaskStatus      `json:"status"`                // Current task status
+	CreatedAt        time.Time       `json:"created_at"`            // Task creation timestamp
+	StartedAt        *time.Time      `json:"started_at,omitempty"`  // Execution start timestamp (if started)
+	FinishedAt       *time.Time      `json:"finished_at,omitempty"` // Completion timestamp (if finished)
+	QueuedDurationMs int64           `json:"queued_duration_ms"`    // Time spent waiting in the queue, in milliseconds
+	RunDurationMs    int64           `json:"run_duration_ms"`       // Time spent running, in milliseconds
+	Duration         string          `json:"duration,omitempty"`    // Human-readable run time (if started)
+	Result           string          `json:"result,omitempty"`      // Result message or error
+	Params           json.RawMessage `json:"params,omitempty"`      // Type-specific task parameters
+	Output           json.RawMessage `json:"output,omitempty"`      // Structured output produced by the task
+	BatchID          string          `json:"batch_id,omitempty"`    // ID of the batch the task was submitted in
+	Owner            string          `json:"owner,omitempty"`       // Identity of the client that created the task
+	RequestID        string          `json:"request_id,omitempty"`  // ID of the HTTP request that queued the task
+	TraceParent      string          `json:"traceparent,omitempty"` // W3C trace context of the request that queued the task
+	RetryOf          string          `json:"retry_of,omitempty"`    // ID of the task this one retries
+	Attempts         []TaskAttempt   `json:"attempts,omitempty"`    // Previous runs of a task re-queued in place, oldest first
+}
+
+// TaskAttempt records a previous run of a task that was re-queued in place.
+type TaskAttempt struct {
+	QueuedAt   time.Time  `json:"queued_at"`             // When the attempt was queued
+	StartedAt  *time.Time `json:"started_at,omitempty"`  // Execution start timestamp (if started)
+	FinishedAt *time.Time `json:"finished_at,omitempty"` // Completion timestamp
+	Status     TaskStatus `json:"status"`                // Final status of the attempt
+	Result     string     `json:"result,omitempty"`      // Result message or error
+}
+
+// NewTask creates and returns a new pending Task created at the given time.
+func NewTask(id, taskType string, createdAt time.Time) *Task {
+	return &Task{ID: id, Type: taskType, Status: TaskStatusPending, CreatedAt: createdAt}
 }
 
-// NewTask creates and returns a new Task with default status and creation time.
-func NewTask(id, taskType string) *Task {
-	return &Task{ID: id, Type: taskType, Status: TaskStatusPending, CreatedAt: time.Now()}
+// Snapshot returns a copy of the task with the durations derived from its timestamps.
+// Unfinished phases are measured up to now.
+func (t *Task) Snapshot(now time.Time) *Task {
+	s := *t
+	s.Attempts = slices.Clone(t.Attempts)
+
+	queuedUntil := now
+	if t.StartedAt != nil {
+		queuedUntil = *t.StartedAt
+	} else if t.FinishedAt != nil {
+		queuedUntil = *t.FinishedAt
+	}
+	s.QueuedDurationMs = queuedUntil.Sub(t.CreatedAt).Milliseconds()
+
+	if t.StartedAt != nil {
+		runUntil := now
+		if t.FinishedAt != nil {
+			runUntil = *t.FinishedAt
+		}
+		run := runUntil.Sub(*t.StartedAt)
+		s.RunDurationMs = run.Milliseconds()
+		s.Duration = run.Truncate(time.Millisecond).String()
+	}
+
+	return &s
 }
@@ -0,0 +1,24 @@
+package model
+
+import "encoding/json"
+
+// TaskType describes a registered task type with its limits and live statistics.
+type TaskType struct {
+	Type         string          `json:"type"`                    // Task type identifier
+	Description  string          `json:"description,omitempty"`   // Human-readable description
+	ParamsSchema json.RawMessage `json:"params_schema,omitempty"` // JSON schema of the task parameters
+	Disabled     bool            `json:"disabled"`                // New tasks are rejected
+	QueueSize    int             `json:"queue_size"`              // Max number of pending and running tasks
+	Concurrency  int             `json:"concurrency"`             // Max number of tasks running at once
+	TimeoutMs    int64           `json:"timeout_ms"`              // Max execution time per task (0 means no limit)
+	Stats        TaskTypeStats   `json:"stats"`                   // Live task counters
+}
+
+// TaskTypeStats holds live task counters of a task type.
+type TaskTypeStats struct {
+	Pending  int `json:"pending"`  // Tasks waiting in the queue
+	Running  int `json:"running"`  // Tasks being executed
+	Done     int `json:"done"`     // Tasks completed successfully
+	Failed   int `json:"failed"`   // Tasks finished with an error
+	Canceled int `json:"canceled"` // Tasks canceled before completion
+}
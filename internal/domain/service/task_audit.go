@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/logging"
+)
+
+// taskAudit returns the audit entry of an action on a task changing its status from before to after,
+// requested by the actor carried by ctx.
+func (m *TaskManager) taskAudit(ctx context.Context, action model.AuditAction, t *model.Task, before, after model.TaskStatus) *model.AuditEntry {
+	e := m.queueAudit(ctx, action, t.Type)
+	e.TaskID = t.ID
+	e.RetryOf = t.RetryOf
+	e.Before = before
+	e.After = after
+
+	return e
+}
+
+// queueAudit returns the audit entry of an action on the queue of a task type,
+// requested by the actor carried by ctx.
+func (m *TaskManager) queueAudit(ctx context.Context, action model.AuditAction, taskType string) *model.AuditEntry {
+	return &model.AuditEntry{
+		Time:      m.clock.Now(),
+		Actor:     audit.Actor(ctx),
+		Action:    action,
+		TaskType:  taskType,
+		RequestID: logging.RequestID(ctx),
+	}
+}
+
+// audit records the entries with the auditor, if any, before the operation they describe is applied.
+// A failure is logged with its cause and reported as ErrAuditFailed, in which case the operation must not be applied.
+// Recording under the lock keeps the entries in the order the operations are applied and never lets
+// an operation through without its entry, at the cost of blocking the manager while the auditor writes
+// (e.g. during an fsync of the audit log, see audit.WithSync).
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) audit(entries ...*model.AuditEntry) error {
+	if m.auditor == nil || len(entries) == 0 {
+		return nil
+	}
+
+	if err := m.auditor.Record(entries...); err != nil {
+		m.logger.LogAttrs(context.Background(), slog.LevelError, "audit failed",
+			slog.String("action", string(entries[0].Action)),
+			slog.String("actor", entries[0].Actor),
+			slog.Int("entries", len(entries)),
+			slog.String("error", err.Error()),
+		)
+		return ErrAuditFailed
+	}
+
+	return nil
+}
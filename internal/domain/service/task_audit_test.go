@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/logging"
+)
+
+// memoryAuditor keeps the recorded entries in memory, or fails once err is set.
+type memoryAuditor struct {
+	mu      sync.Mutex
+	entries []*model.AuditEntry
+	err     error
+}
+
+// Record keeps the entries unless the auditor fails.
+func (a *memoryAuditor) Record(entries ...*model.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.err != nil {
+		return a.err
+	}
+	a.entries = append(a.entries, entries...)
+
+	return nil
+}
+
+// recorded returns the entries recorded so far.
+func (a *memoryAuditor) recorded() []*model.AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]*model.AuditEntry{}, a.entries...)
+}
+
+// TestAuditor checks that state-changing calls are audited with the actor, request ID, and status change.
+func TestAuditor(t *testing.T) {
+	auditor := &memoryAuditor{}
+	manager, _ := newFakeManager(service.WithAuditor(auditor))
+	manager.RegisterFactory("blocked", &blockingFactory{})
+
+	ctx := logging.WithRequestID(audit.WithActor(context.Background(), "alice"), "req-1")
+	running, _ := manager.CreateTaskFor(ctx, "alice", "blocked", nil)
+	waitForStatus(t, manager, running.ID, model.TaskStatusRunning)
+	pending, _ := manager.CreateTaskFor(ctx, "alice", "blocked", nil)
+
+	if err := manager.CancelTask(ctx, pending.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	retry, err := manager.RetryTask(ctx, pending.ID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.DeleteTask(context.Background(), pending.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []model.AuditEntry{
+		{Action: model.AuditActionCreate, TaskID: running.ID, Actor: "alice", After: model.TaskStatusPending},
+		{Action: model.AuditActionCreate, TaskID: pending.ID, Actor: "alice", After: model.TaskStatusPending},
+		{Action: model.AuditActionCancel, TaskID: pending.ID, Actor: "alice", Before: model.TaskStatusPending, After: model.TaskStatusCanceled},
+		{Action: model.AuditActionRetry, TaskID: retry.ID, Actor: "alice", RetryOf: pending.ID, After: model.TaskStatusPending},
+		{Action: model.AuditActionDelete, TaskID: pending.ID, Actor: audit.Anonymous, Before: model.TaskStatusCanceled},
+	}
+
+	entries := auditor.recorded()
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, e := range entries {
+		want := expected[i]
+		if e.Action != want.Action || e.TaskID != want.TaskID || e.Actor != want.Actor || e.RetryOf != want.RetryOf ||
+			e.Before != want.Before || e.After != want.After || e.TaskType != "blocked" {
+			t.Errorf("entry %d: expected %+v, got %+v", i, want, *e)
+		}
+		if want.Actor == "alice" && e.RequestID != "req-1" {
+			t.Errorf("entry %d: expected request ID req-1, got %q", i, e.RequestID)
+		}
+	}
+}
+
+// TestAuditor_Failure checks that calls whose audit entries cannot be recorded fail and change nothing.
+func TestAuditor_Failure(t *testing.T) {
+	auditor := &memoryAuditor{}
+	manager, _ := newFakeManager(service.WithAuditor(auditor))
+	manager.RegisterFactory("blocked", &blockingFactory{})
+
+	running, _ := manager.CreateTask("blocked")
+	waitForStatus(t, manager, running.ID, model.TaskStatusRunning)
+	pending, _ := manager.CreateTask("blocked")
+
+	auditor.err = errors.New("disk full")
+
+	if _, err := manager.CreateTask("blocked"); !errors.Is(err, service.ErrAuditFailed) {
+		t.Errorf("expected ErrAuditFailed on create, got %v", err)
+	}
+	if _, _, err := manager.CreateBatch(context.Background(), "", model.BatchModeAtomic, []service.TaskSpec{{Type: "blocked"}}); !errors.Is(err, service.ErrAuditFailed) {
+		t.Errorf("expected ErrAuditFailed on atomic batch, got %v", err)
+	}
+	if err := manager.CancelTask(context.Background(), pending.ID); !errors.Is(err, service.ErrAuditFailed) {
+		t.Errorf("expected ErrAuditFailed on cancel, got %v", err)
+	}
+	if _, err := manager.PurgeQueue(context.Background(), "blocked"); !errors.Is(err, service.ErrAuditFailed) {
+		t.Errorf("expected ErrAuditFailed on purge, got %v", err)
+	}
+
+	if tasks := manager.ListTasks(service.TaskFilter{}); len(tasks) != 2 {
+		t.Errorf("expected no task to be created or removed, got %d tasks", len(tasks))
+	}
+	if found, _ := manager.GetTask(pending.ID); found.Status != model.TaskStatusPending {
+		t.Errorf("expected the task to stay pending, got %q", found.Status)
+	}
+	if got := len(auditor.recorded()); got != 2 {
+		t.Errorf("expected only the 2 first creations to be recorded, got %d", got)
+	}
+}
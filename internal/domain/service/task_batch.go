@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// maxBatchSize is the max number of tasks submitted in one batch.
+const maxBatchSize = 1000
+
+// TaskSpec describes a task to be created in a batch.
+type TaskSpec struct {
+	Type   string          // Task type
+	Params json.RawMessage // Type-specific task parameters
+}
+
+// BatchItem is the result of creating one task of a batch.
+type BatchItem struct {
+	Task *model.Task // Created task (nil on error)
+	Err  error       // Reason the task was not created
+}
+
+// batch records the tasks submitted together.
+type batch struct {
+	owner     string
+	mode      model.BatchMode
+	createdAt time.Time
+	taskIDs   []string
+}
+
+// CreateBatch creates the tasks of a batch sharing a new batch ID and returns one item per spec.
+// The batch and its tasks record the given owner (client identity), and the tasks the request ID carried by ctx.
+// In atomic mode the tasks are validated against the type registry, parameters, queue capacity,
+// and the limits of the owner first, and nothing is created if any of them fails or if the tasks cannot be audited.
+// In best-effort mode each task is created independently and failures are reported in its item;
+// if none of them is created, no batch is recorded and the returned batch ID is empty.
+// A batch is forgotten once all its tasks are deleted.
+func (m *TaskManager) CreateBatch(ctx context.Context, owner string, mode model.BatchMode, specs []TaskSpec) (string, []BatchItem, error) {
+	if !mode.IsValid() {
+		return "", nil, fmt.Errorf("cannot create batch with mode %q: %w", mode, ErrTaskInvalidBatch)
+	}
+	if len(specs) == 0 || len(specs) > maxBatchSize {
+		return "", nil, fmt.Errorf("cannot create batch of %d tasks (1..%d allowed): %w", len(specs), maxBatchSize, ErrTaskInvalidBatch)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if mode == model.BatchModeAtomic {
+		queued := make(map[string]int)
+		for i, spec := range specs {
+			if err := m.checkCreate(spec.Type, spec.Params, queued[spec.Type]); err != nil {
+				return "", nil, fmt.Errorf("cannot create batch, task %d: %w", i, err)
+			}
+			queued[spec.Type]++
+		}
+		if err := m.checkClient(owner, len(specs)); err != nil {
+			return "", nil, fmt.Errorf("cannot create batch: %w", err)
+		}
+	}
+
+	id := m.generateID()
+	now := m.clock.Now()
+	b := &batch{owner: owner, mode: mode, createdAt: now}
+	items := make([]BatchItem, len(specs))
+	tasks := make([]*model.Task, len(specs))
+
+	// Atomic batches are audited at once, so that no task is queued unless all of them are recorded.
+	if mode == model.BatchModeAtomic {
+		entries := make([]*model.AuditEntry, len(specs))
+		for i, spec := range specs {
+			t, err := m.newTask(ctx, owner, spec.Type, spec.Params, id)
+			if err != nil {
+				return "", nil, fmt.Errorf("cannot create batch, task %d: %w", i, err)
+			}
+			tasks[i] = t
+			entries[i] = m.taskAudit(ctx, model.AuditActionCreate, t, "", t.Status)
+		}
+		if err := m.audit(entries...); err != nil {
+			return "", nil, fmt.Errorf("cannot create batch: %w", err)
+		}
+		m.chargeClient(owner, len(specs))
+	}
+
+	for i, spec := range specs {
+		t := tasks[i]
+		if t == nil {
+			var err error
+			if t, err = m.prepareBatchTask(ctx, owner, spec, id); err != nil {
+				items[i].Err = err
+				continue
+			}
+		}
+
+		m.addTask(t)
+		b.taskIDs = append(b.taskIDs, t.ID)
+		items[i].Task = t.Snapshot(now)
+	}
+
+	if len(b.taskIDs) == 0 {
+		return "", items, nil
+	}
+	m.batches[id] = b
+
+	return id, items, nil
+}
+
+// prepareBatchTask checks and audits a task of a best-effort batch on its own,
+// and returns it ready to be queued.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) prepareBatchTask(ctx context.Context, owner string, spec TaskSpec, batchID string) (*model.Task, error) {
+	if err := m.checkCreate(spec.Type, spec.Params, 0); err != nil {
+		return nil, err
+	}
+	if err := m.checkClient(owner, 1); err != nil {
+		return nil, err
+	}
+
+	t, err := m.newTask(ctx, owner, spec.Type, spec.Params, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.audit(m.taskAudit(ctx, model.AuditActionCreate, t, "", t.Status)); err != nil {
+		return nil, fmt.Errorf("cannot create task with type %q: %w", spec.Type, err)
+	}
+	m.chargeClient(owner, 1)
+
+	return t, nil
+}
+
+// pruneBatch forgets a batch once none of its tasks is left in the task list or the dead letters.
+// WARNING: Must be called with m.mu.Lock held, after a task of the batch is removed.
+func (m *TaskManager) pruneBatch(id string) {
+	b, batchExists := m.batches[id]
+	if !batchExists {
+		return
+	}
+	for _, taskID := range b.taskIDs {
+		if _, taskExists := m.findTask(taskID); taskExists {
+			return
+		}
+	}
+
+	delete(m.batches, id)
+}
+
+// GetBatch returns the aggregate progress of a batch by ID or an error if not found.
+func (m *TaskManager) GetBatch(id string) (*model.Batch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	b, batchExists := m.batches[id]
+	if !batchExists {
+		return nil, fmt.Errorf("cannot find batch with ID %q: %w", id, ErrBatchNotFound)
+	}
+
+	result := &model.Batch{
+		ID:        id,
+		Mode:      b.mode,
+		Owner:     b.owner,
+		CreatedAt: b.createdAt,
+		Total:     len(b.taskIDs),
+		TaskIDs:   append([]string{}, b.taskIDs...),
+		Finished:  true,
+	}
+
+	for _, taskID := range b.taskIDs {
+		t, taskExists := m.findTask(taskID)
+		if !taskExists {
+			result.Stats.Deleted++
+			continue
+		}
+
+		switch t.Status {
+		case model.TaskStatusPending:
+			result.Stats.Pending++
+		case model.TaskStatusRunning:
+			result.Stats.Running++
+		case model.TaskStatusDone:
+			result.Stats.Done++
+		case model.TaskStatusFailed:
+			result.Stats.Failed++
+		case model.TaskStatusCanceled:
+			result.Stats.Canceled++
+		}
+		if !t.Status.IsFinal() {
+			result.Finished = false
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// TestCreateBatch_Atomic checks that an atomic batch is rejected as a whole when it exceeds the queue capacity.
+func TestCreateBatch_Atomic(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactoryWithConfig("blocked", &blockingFactory{}, service.TypeConfig{QueueSize: 2, Concurrency: 1})
+
+	specs := []service.TaskSpec{{Type: "blocked"}, {Type: "blocked"}, {Type: "blocked"}}
+	if _, _, err := manager.CreateBatch(context.Background(), "", model.BatchModeAtomic, specs); !errors.Is(err, service.ErrTaskQueueLimitReached) {
+		t.Fatalf("expected ErrTaskQueueLimitReached, got %v", err)
+	}
+	if tasks := manager.ListTasks(service.TaskFilter{}); len(tasks) != 0 {
+		t.Fatalf("expected no tasks to be created, got %d", len(tasks))
+	}
+
+	id, items, err := manager.CreateBatch(context.Background(), "", model.BatchModeAtomic, specs[:2])
+	if err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+	for i, item := range items {
+		if item.Err != nil || item.Task.BatchID != id {
+			t.Errorf("item %d: expected a task of batch %q, got %+v", i, id, item)
+		}
+	}
+}
+
+// TestCreateBatch_BestEffort checks that a best-effort batch creates the valid tasks and reports the others.
+func TestCreateBatch_BestEffort(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactoryWithConfig("blocked", &blockingFactory{}, service.TypeConfig{QueueSize: 1, Concurrency: 1})
+
+	specs := []service.TaskSpec{{Type: "blocked"}, {Type: "unknown"}, {Type: "blocked"}}
+	id, items, err := manager.CreateBatch(context.Background(), "", model.BatchModeBestEffort, specs)
+	if err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+
+	if items[0].Task == nil || items[0].Err != nil {
+		t.Errorf("expected the first task to be created, got %+v", items[0])
+	}
+	if !errors.Is(items[1].Err, service.ErrTaskUnknownType) {
+		t.Errorf("expected ErrTaskUnknownType for the second task, got %v", items[1].Err)
+	}
+	if !errors.Is(items[2].Err, service.ErrTaskQueueLimitReached) {
+		t.Errorf("expected ErrTaskQueueLimitReached for the third task, got %v", items[2].Err)
+	}
+
+	batch, err := manager.GetBatch(id)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if batch.Total != 1 || batch.TaskIDs[0] != items[0].Task.ID {
+		t.Errorf("expected the batch to hold only the created task, got %+v", batch)
+	}
+}
+
+// TestCreateBatch_BestEffortNoneCreated checks that a best-effort batch without any created task is not recorded.
+func TestCreateBatch_BestEffortNoneCreated(t *testing.T) {
+	manager, _ := newFakeManager()
+
+	id, items, err := manager.CreateBatch(context.Background(), "", model.BatchModeBestEffort, []service.TaskSpec{{Type: "unknown"}})
+	if err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+	if id != "" || !errors.Is(items[0].Err, service.ErrTaskUnknownType) {
+		t.Errorf("expected no batch ID and ErrTaskUnknownType, got %q and %+v", id, items[0])
+	}
+}
+
+// TestCreateBatch_Invalid checks that empty batches and unknown modes are rejected.
+func TestCreateBatch_Invalid(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	if _, _, err := manager.CreateBatch(context.Background(), "", model.BatchModeAtomic, nil); !errors.Is(err, service.ErrTaskInvalidBatch) {
+		t.Errorf("expected ErrTaskInvalidBatch for an empty batch, got %v", err)
+	}
+	if _, _, err := manager.CreateBatch(context.Background(), "", "all", []service.TaskSpec{{Type: "mock"}}); !errors.Is(err, service.ErrTaskInvalidBatch) {
+		t.Errorf("expected ErrTaskInvalidBatch for an unknown mode, got %v", err)
+	}
+}
+
+// TestGetBatch checks the aggregate progress of a batch as its tasks finish or are deleted.
+func TestGetBatch(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	id, items, err := manager.CreateBatch(context.Background(), "", model.BatchModeAtomic, []service.TaskSpec{{Type: "mock"}, {Type: "mock"}})
+	if err != nil {
+		t.Fatalf("CreateBatch failed: %v", err)
+	}
+	for _, item := range items {
+		waitUntilDone(t, manager, item.Task.ID)
+	}
+	if err := manager.DeleteTask(context.Background(), items[1].Task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	batch, err := manager.GetBatch(id)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if batch.Total != 2 || batch.Stats.Done != 1 || batch.Stats.Deleted != 1 || !batch.Finished {
+		t.Errorf("expected one done and one deleted task, got %+v", batch)
+	}
+
+	if err := manager.DeleteTask(context.Background(), items[0].Task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := manager.GetBatch(id); !errors.Is(err, service.ErrBatchNotFound) {
+		t.Errorf("expected the batch to be forgotten once all its tasks are deleted, got %v", err)
+	}
+
+	if _, err := manager.GetBatch("missing"); !errors.Is(err, service.ErrBatchNotFound) {
+		t.Errorf("expected ErrBatchNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// DeleteTasks removes the tasks matching the filter on behalf of the actor carried by ctx
+// and returns their IDs, oldest first, removing failed tasks from the dead letters.
+// Running tasks are never deleted and are skipped even if they match.
+// With dryRun set, nothing is removed and the IDs of the tasks that would be deleted are returned.
+// Nothing is removed if the deletions cannot be audited.
+func (m *TaskManager) DeleteTasks(ctx context.Context, filter TaskFilter, dryRun bool) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tasks := slices.DeleteFunc(m.matchingTasks(filter), func(t *model.Task) bool {
+		return t.Status == model.TaskStatusRunning
+	})
+
+	ids := make([]string, 0, len(tasks))
+	entries := make([]*model.AuditEntry, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+		entries = append(entries, m.taskAudit(ctx, model.AuditActionDelete, t, t.Status, ""))
+	}
+	if dryRun {
+		return ids, nil
+	}
+	if err := m.audit(entries...); err != nil {
+		return nil, fmt.Errorf("cannot delete %d tasks: %w", len(tasks), err)
+	}
+
+	for _, t := range tasks {
+		m.deleteTask(t)
+	}
+
+	return ids, nil
+}
+
+// PurgeQueue removes all pending tasks of the given type on behalf of the actor carried by ctx
+// and returns their IDs in queue order. Running tasks are not affected.
+func (m *TaskManager) PurgeQueue(ctx context.Context, taskType string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, typeExists := m.factories[taskType]; !typeExists {
+		return nil, fmt.Errorf("cannot purge queue of task type %q: %w", taskType, ErrTaskUnknownType)
+	}
+
+	queued := append([]*model.Task{}, m.queues[taskType]...)
+
+	ids := make([]string, 0, len(queued))
+	entries := make([]*model.AuditEntry, 0, len(queued))
+	for _, t := range queued {
+		ids = append(ids, t.ID)
+		entries = append(entries, m.taskAudit(ctx, model.AuditActionPurge, t, t.Status, ""))
+	}
+	if err := m.audit(entries...); err != nil {
+		return nil, fmt.Errorf("cannot purge queue of task type %q: %w", taskType, err)
+	}
+
+	for _, t := range queued {
+		m.deleteTask(t)
+	}
+
+	// A draining type is removed by its worker once the queue is empty.
+	m.notifyWorker(taskType)
+
+	return ids, nil
+}
@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// TestDeleteTasks checks that matching tasks are deleted, running ones are skipped, and dry runs change nothing.
+func TestDeleteTasks(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("blocked", &blockingFactory{})
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	old, _ := manager.CreateTask("mock")
+	running, _ := manager.CreateTask("blocked")
+	waitUntilDone(t, manager, old.ID)
+	waitForStatus(t, manager, running.ID, model.TaskStatusRunning)
+	fake.Advance(time.Hour)
+	recent, _ := manager.CreateTask("mock")
+	waitUntilDone(t, manager, recent.ID)
+
+	filter := service.TaskFilter{CreatedBefore: fake.Now().Add(-time.Minute)}
+
+	ids, err := manager.DeleteTasks(context.Background(), filter, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids, []string{old.ID}) {
+		t.Fatalf("expected dry run to report only the old task, got %v", ids)
+	}
+	if _, err := manager.GetTask(old.ID); err != nil {
+		t.Fatalf("expected dry run to keep the task, got %v", err)
+	}
+
+	ids, err = manager.DeleteTasks(context.Background(), filter, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids, []string{old.ID}) {
+		t.Fatalf("expected only the old task to be deleted, got %v", ids)
+	}
+	if _, err := manager.GetTask(old.ID); !errors.Is(err, service.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound for the deleted task, got %v", err)
+	}
+	if tasks := manager.ListTasks(service.TaskFilter{}); len(tasks) != 2 {
+		t.Errorf("expected the running and recent tasks to remain, got %d", len(tasks))
+	}
+}
+
+// TestPurgeQueue checks that purging drops pending tasks only and frees their queue slots.
+func TestPurgeQueue(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactoryWithConfig("blocked", &blockingFactory{}, service.TypeConfig{QueueSize: 3, Concurrency: 1})
+
+	running, _ := manager.CreateTask("blocked")
+	waitForStatus(t, manager, running.ID, model.TaskStatusRunning)
+	first, _ := manager.CreateTask("blocked")
+	second, _ := manager.CreateTask("blocked")
+
+	ids, err := manager.PurgeQueue(context.Background(), "blocked")
+	if err != nil {
+		t.Fatalf("PurgeQueue failed: %v", err)
+	}
+	if !slices.Equal(ids, []string{first.ID, second.ID}) {
+		t.Fatalf("expected the pending tasks to be purged in queue order, got %v", ids)
+	}
+	if _, err := manager.GetTask(running.ID); err != nil {
+		t.Errorf("expected the running task to remain, got %v", err)
+	}
+
+	for range 2 {
+		if _, err := manager.CreateTask("blocked"); err != nil {
+			t.Errorf("expected purged slots to be reusable, got %v", err)
+		}
+	}
+
+	if _, err := manager.PurgeQueue(context.Background(), "unknown"); !errors.Is(err, service.ErrTaskUnknownType) {
+		t.Errorf("expected ErrTaskUnknownType, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// ClientLimits holds the limits applied to a client, identified by the owner recorded in its tasks.
+// Zero values mean no limit. Tasks without an owner are not limited.
+type ClientLimits struct {
+	Rate       float64 // Tasks created per second, refilled continuously
+	Burst      int     // Max tasks created at once (defaults to the rate rounded up)
+	MaxPending int     // Max pending tasks across all types
+	Weight     int     // Share of the workers of a type when several clients have queued tasks (default 1)
+}
+
+// Resolve returns the limits with zero values inherited from the given defaults.
+func (l ClientLimits) Resolve(defaults ClientLimits) ClientLimits {
+	if l.Rate == 0 {
+		l.Rate = defaults.Rate
+	}
+	if l.Burst == 0 {
+		l.Burst = defaults.Burst
+	}
+	if l.MaxPending == 0 {
+		l.MaxPending = defaults.MaxPending
+	}
+	if l.Weight == 0 {
+		l.Weight = defaults.Weight
+	}
+
+	return l
+}
+
+// normalize replaces invalid limits with the built-in defaults.
+func (l ClientLimits) normalize() ClientLimits {
+	l.Rate = max(l.Rate, 0)
+	l.MaxPending = max(l.MaxPending, 0)
+	if l.Rate > 0 && l.Burst < 1 {
+		l.Burst = int(math.Ceil(l.Rate))
+	}
+	if l.Weight < 1 {
+		l.Weight = 1
+	}
+
+	return l
+}
+
+// ClientLimitError is returned when a client exceeds its rate limit or its quota of pending tasks,
+// or submits more tasks at once than its burst. It matches ErrClientRateLimited, ErrClientQuotaReached,
+// or ErrClientBurstExceeded with errors.Is.
+type ClientLimitError struct {
+	Owner      string        // Client that exceeded the limit
+	Limits     ClientLimits  // Limits of the client
+	RetryAfter time.Duration // Time until the rate limit lets the request through (0 for the quota and the burst)
+	Err        error         // ErrClientRateLimited, ErrClientQuotaReached, or ErrClientBurstExceeded
+}
+
+// Error describes the exceeded limit.
+func (e *ClientLimitError) Error() string {
+	switch {
+	case e.RetryAfter > 0:
+		return fmt.Sprintf("cannot create task for client %q: %v (%g tasks/s, burst %d), retry in %s",
+			e.Owner, e.Err, e.Limits.Rate, e.Limits.Burst, e.RetryAfter.Round(time.Millisecond))
+	case errors.Is(e.Err, ErrClientBurstExceeded):
+		return fmt.Sprintf("cannot create task for client %q: %v (max %d tasks at once)", e.Owner, e.Err, e.Limits.Burst)
+	}
+
+	return fmt.Sprintf("cannot create task for client %q: %v (max %d pending tasks)", e.Owner, e.Err, e.Limits.MaxPending)
+}
+
+// Unwrap returns the sentinel error of the exceeded limit.
+func (e *ClientLimitError) Unwrap() error {
+	return e.Err
+}
+
+// SetClientLimits replaces the default limits of clients and their per-client overrides.
+// Zero values of an override are inherited from the defaults. Token buckets keep their level.
+func (m *TaskManager) SetClientLimits(defaults ClientLimits, overrides map[string]ClientLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setClientLimits(defaults, overrides)
+}
+
+// setClientLimits is SetClientLimits with the manager already locked or not shared yet.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) setClientLimits(defaults ClientLimits, overrides map[string]ClientLimits) {
+	m.clientDefaults = defaults
+	m.clientOverrides = make(map[string]ClientLimits, len(overrides))
+	for owner, l := range overrides {
+		m.clientOverrides[owner] = l
+	}
+}
+
+// ClientLimits returns the resolved limits of a client.
+func (m *TaskManager) ClientLimits(owner string) ClientLimits {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.clientLimits(owner)
+}
+
+// clientLimits returns the resolved limits of a client.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) clientLimits(owner string) ClientLimits {
+	return m.clientOverrides[owner].Resolve(m.clientDefaults).normalize()
+}
+
+// checkClient returns a *ClientLimitError unless the owner may queue n more tasks.
+// Nothing is taken from its bucket: the tasks are charged with chargeClient once they are queued.
+// WARNING: Must be called with m.mu.Lock held, after checkCreate.
+func (m *TaskManager) checkClient(owner string, n int) error {
+	if owner == "" {
+		return nil
+	}
+
+	limits := m.clientLimits(owner)
+
+	if limits.MaxPending > 0 && m.pendingOf(owner)+n > limits.MaxPending {
+		return &ClientLimitError{Owner: owner, Limits: limits, Err: ErrClientQuotaReached}
+	}
+
+	if limits.Rate > 0 {
+		// The bucket never holds more than the burst, so waiting would not help.
+		if n > limits.Burst {
+			return &ClientLimitError{Owner: owner, Limits: limits, Err: ErrClientBurstExceeded}
+		}
+		if wait := m.bucket(owner, limits).wait(n, limits, m.clock.Now()); wait > 0 {
+			return &ClientLimitError{Owner: owner, Limits: limits, RetryAfter: wait, Err: ErrClientRateLimited}
+		}
+	}
+
+	return nil
+}
+
+// chargeClient takes n tokens from the bucket of the owner for tasks allowed by checkClient.
+// WARNING: Must be called with m.mu.Lock held, after checkClient.
+func (m *TaskManager) chargeClient(owner string, n int) {
+	if owner == "" {
+		return
+	}
+
+	if limits := m.clientLimits(owner); limits.Rate > 0 {
+		m.bucket(owner, limits).take(n, limits, m.clock.Now())
+	}
+}
+
+// bucket returns the token bucket of the owner, creating a full one if needed.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) bucket(owner string, limits ClientLimits) *tokenBucket {
+	b, ok := m.buckets[owner]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limits.Burst), updated: m.clock.Now()}
+		m.buckets[owner] = b
+	}
+
+	return b
+}
+
+// pendingOf returns the number of pending tasks of the owner across all types.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) pendingOf(owner string) int {
+	var n int
+	for _, q := range m.queues {
+		for _, t := range q {
+			if t.Owner == owner {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// tokenBucket limits the rate of task creation of one client.
+type tokenBucket struct {
+	tokens  float64   // Tokens left as of updated
+	updated time.Time // Time of the last refill
+}
+
+// refill adds the tokens accumulated since the last refill, up to the burst.
+func (b *tokenBucket) refill(limits ClientLimits, now time.Time) {
+	b.tokens = min(float64(limits.Burst), b.tokens+now.Sub(b.updated).Seconds()*limits.Rate)
+	b.updated = now
+}
+
+// wait refills the bucket and returns the time until n tokens are left (0 if they already are).
+func (b *tokenBucket) wait(n int, limits ClientLimits, now time.Time) time.Duration {
+	b.refill(limits, now)
+
+	if b.tokens >= float64(n) {
+		return 0
+	}
+
+	missing := float64(n) - b.tokens
+	return time.Duration(math.Ceil(missing / limits.Rate * float64(time.Second)))
+}
+
+// take refills the bucket and takes n tokens.
+func (b *tokenBucket) take(n int, limits ClientLimits, now time.Time) {
+	b.refill(limits, now)
+	b.tokens = max(b.tokens-float64(n), 0)
+}
+
+// dequeueTask removes and returns the next task to start from the queue of the type.
+// Clients with queued tasks take turns in proportion to their weights: the oldest task of the client
+// that was served least relative to its weight is picked, so one client's backlog cannot starve others.
+// With a single client the queue is served in creation order.
+// WARNING: Must be called with m.mu.Lock held, with a non-empty queue.
+func (m *TaskManager) dequeueTask(taskType string) *model.Task {
+	q := m.queues[taskType]
+	served := m.served[taskType]
+
+	next := 0
+	for i, t := range q {
+		if served[t.Owner] < served[q[next].Owner] {
+			next = i
+		}
+	}
+
+	t := q[next]
+	m.queues[taskType] = append(q[:next], q[next+1:]...)
+
+	if len(m.queues[taskType]) == 0 {
+		clear(served)
+	} else {
+		served[t.Owner] += 1 / float64(m.clientLimits(t.Owner).Weight)
+	}
+
+	return t
+}
+
+// joinFairQueue lets the owner of a task queued after being idle start level with the clients
+// already waiting, so that it neither makes up for the service it did not use nor pays for earlier service.
+// WARNING: Must be called with m.mu.Lock held, before the task is added to the queue.
+func (m *TaskManager) joinFairQueue(t *model.Task) {
+	served := m.served[t.Type]
+	if served == nil {
+		return
+	}
+
+	level, waiting := 0.0, false
+	for _, queued := range m.queues[t.Type] {
+		if queued.Owner == t.Owner {
+			return
+		}
+		if s := served[queued.Owner]; !waiting || s < level {
+			level, waiting = s, true
+		}
+	}
+
+	served[t.Owner] = level
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+)
+
+type (
+	// recordingFactory creates tasks that record the owner of every started task, in order.
+	recordingFactory struct {
+		mu     sync.Mutex
+		owners []string
+	}
+	recordingTask struct{} // recordingTask succeeds right away.
+)
+
+// New records the owner of the task.
+func (f *recordingFactory) New(t *model.Task) task.ExecutableTask {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.owners = append(f.owners, t.Owner)
+	return recordingTask{}
+}
+
+// Run does nothing.
+func (recordingTask) Run(_ context.Context) error {
+	return nil
+}
+
+// TestClientLimits_Rate checks that each client has its own token bucket refilled over time.
+func TestClientLimits_Rate(t *testing.T) {
+	manager, fake := newFakeManager(service.WithClientLimits(service.ClientLimits{Rate: 1, Burst: 2}, nil))
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	for range 2 {
+		if _, err := manager.CreateTaskFor(context.Background(), "a", "mock", nil); err != nil {
+			t.Fatalf("expected burst to be allowed, got %v", err)
+		}
+	}
+
+	_, err := manager.CreateTaskFor(context.Background(), "a", "mock", nil)
+	var limitErr *service.ClientLimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, service.ErrClientRateLimited) || limitErr.RetryAfter != time.Second {
+		t.Fatalf("expected rate limit error with a 1s retry, got %v", err)
+	}
+
+	if _, err := manager.CreateTaskFor(context.Background(), "b", "mock", nil); err != nil {
+		t.Errorf("expected another client to be allowed, got %v", err)
+	}
+	if _, err := manager.CreateTask("mock"); err != nil {
+		t.Errorf("expected tasks without owner not to be limited, got %v", err)
+	}
+
+	fake.Advance(time.Second)
+	if _, err := manager.CreateTaskFor(context.Background(), "a", "mock", nil); err != nil {
+		t.Errorf("expected refilled bucket to allow a task, got %v", err)
+	}
+}
+
+// TestClientLimits_Burst checks that batches larger than the burst are refused for good
+// and that tasks that are not queued take no tokens.
+func TestClientLimits_Burst(t *testing.T) {
+	auditor := &memoryAuditor{}
+	manager, _ := newFakeManager(service.WithClientLimits(service.ClientLimits{Rate: 1, Burst: 2}, nil), service.WithAuditor(auditor))
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	specs := []service.TaskSpec{{Type: "mock"}, {Type: "mock"}, {Type: "mock"}}
+	_, _, err := manager.CreateBatch(context.Background(), "a", model.BatchModeAtomic, specs)
+	var limitErr *service.ClientLimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, service.ErrClientBurstExceeded) || limitErr.RetryAfter != 0 {
+		t.Fatalf("expected ErrClientBurstExceeded without retry, got %v", err)
+	}
+
+	auditor.err = errors.New("disk full")
+	if _, err := manager.CreateTaskFor(context.Background(), "a", "mock", nil); !errors.Is(err, service.ErrAuditFailed) {
+		t.Fatalf("expected ErrAuditFailed, got %v", err)
+	}
+	if _, _, err := manager.CreateBatch(context.Background(), "a", model.BatchModeAtomic, specs[:2]); !errors.Is(err, service.ErrAuditFailed) {
+		t.Fatalf("expected ErrAuditFailed, got %v", err)
+	}
+	auditor.err = nil
+
+	if _, _, err := manager.CreateBatch(context.Background(), "a", model.BatchModeAtomic, specs[:2]); err != nil {
+		t.Errorf("expected the failed creations to take no tokens, got %v", err)
+	}
+}
+
+// TestClientLimits_MaxPending checks the per-client quota of pending tasks and its overrides.
+func TestClientLimits_MaxPending(t *testing.T) {
+	manager, _ := newFakeManager(service.WithClientLimits(
+		service.ClientLimits{MaxPending: 2},
+		map[string]service.ClientLimits{"vip": {MaxPending: 3}},
+	))
+	manager.RegisterFactory("mock", &mockFactory{})
+	if _, err := manager.PauseQueue(context.Background(), "mock", false); err != nil {
+		t.Fatalf("PauseQueue failed: %v", err)
+	}
+
+	for _, owner := range []string{"a", "a", "vip", "vip", "vip"} {
+		if _, err := manager.CreateTaskFor(context.Background(), owner, "mock", nil); err != nil {
+			t.Fatalf("expected task of %s to be queued, got %v", owner, err)
+		}
+	}
+	for _, owner := range []string{"a", "vip"} {
+		if _, err := manager.CreateTaskFor(context.Background(), owner, "mock", nil); !errors.Is(err, service.ErrClientQuotaReached) {
+			t.Errorf("expected ErrClientQuotaReached for %s, got %v", owner, err)
+		}
+	}
+
+	specs := []service.TaskSpec{{Type: "mock"}, {Type: "mock"}, {Type: "mock"}}
+	if _, _, err := manager.CreateBatch(context.Background(), "b", model.BatchModeAtomic, specs); !errors.Is(err, service.ErrClientQuotaReached) {
+		t.Errorf("expected atomic batch over the quota to be rejected, got %v", err)
+	}
+}
+
+// TestClientLimits_Retry checks that retried and requeued tasks count against the limits of their owner.
+func TestClientLimits_Retry(t *testing.T) {
+	manager, _ := newFakeManager(service.WithClientLimits(service.ClientLimits{Rate: 1, Burst: 1}, nil), service.WithDeadLetters(10, nil))
+	manager.RegisterFactory("fail", &failingFactory{})
+
+	tsk, err := manager.CreateTaskFor(context.Background(), "a", "fail", nil)
+	if err != nil {
+		t.Fatalf("CreateTaskFor failed: %v", err)
+	}
+	waitForDeadLetter(t, manager, tsk.ID)
+
+	if _, err := manager.RetryTask(context.Background(), tsk.ID, false); !errors.Is(err, service.ErrClientRateLimited) {
+		t.Errorf("expected the retry to be rate limited, got %v", err)
+	}
+	if _, err := manager.RetryTask(context.Background(), tsk.ID, true); !errors.Is(err, service.ErrClientRateLimited) {
+		t.Errorf("expected the in-place retry to be rate limited, got %v", err)
+	}
+	if _, err := manager.RequeueDeadLetter(context.Background(), tsk.ID); !errors.Is(err, service.ErrClientRateLimited) {
+		t.Errorf("expected the requeue to be rate limited, got %v", err)
+	}
+}
+
+// TestFairScheduling checks that clients take turns in proportion to their weights.
+func TestFairScheduling(t *testing.T) {
+	manager, _ := newFakeManager(service.WithClientLimits(
+		service.ClientLimits{},
+		map[string]service.ClientLimits{"b": {Weight: 2}},
+	))
+	factory := &recordingFactory{}
+	manager.RegisterFactory("rec", factory)
+	if _, err := manager.PauseQueue(context.Background(), "rec", false); err != nil {
+		t.Fatalf("PauseQueue failed: %v", err)
+	}
+
+	for _, owner := range []string{"a", "a", "a", "a", "b", "b", "b", "b"} {
+		if _, err := manager.CreateTaskFor(context.Background(), owner, "rec", nil); err != nil {
+			t.Fatalf("CreateTaskFor failed: %v", err)
+		}
+	}
+	if _, err := manager.ResumeQueue(context.Background(), "rec"); err != nil {
+		t.Fatalf("ResumeQueue failed: %v", err)
+	}
+
+	for _, tsk := range manager.ListTasks(service.TaskFilter{}) {
+		waitUntilDone(t, manager, tsk.ID)
+	}
+
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	want := []string{"a", "b", "b", "a", "b", "b", "a", "a"}
+	for i := range want {
+		if i >= len(factory.owners) || factory.owners[i] != want[i] {
+			t.Fatalf("expected start order %v, got %v", want, factory.owners)
+		}
+	}
+}
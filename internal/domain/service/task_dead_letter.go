@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// deadLetter is a failed task moved out of the task list.
+type deadLetter struct {
+	task   *model.Task
+	deadAt time.Time
+}
+
+// ListDeadLetters returns the dead letters of the given type (or of every type if empty), oldest first.
+func (m *TaskManager) ListDeadLetters(taskType string) []*model.DeadLetter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.clock.Now()
+
+	var letters []*model.DeadLetter
+	for t, ids := range m.deadOrder {
+		if taskType != "" && t != taskType {
+			continue
+		}
+		for _, id := range ids {
+			letters = append(letters, m.dead[id].snapshot(now))
+		}
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		if !letters[i].DeadAt.Equal(letters[j].DeadAt) {
+			return letters[i].DeadAt.Before(letters[j].DeadAt)
+		}
+		return letters[i].Task.ID < letters[j].Task.ID
+	})
+
+	return letters
+}
+
+// GetDeadLetter returns the dead letter of the task with the given ID.
+func (m *TaskManager) GetDeadLetter(id string) (*model.DeadLetter, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	d, deadExists := m.dead[id]
+	if !deadExists {
+		return nil, fmt.Errorf("cannot find dead letter with ID %q: %w", id, ErrDeadLetterNotFound)
+	}
+
+	return d.snapshot(m.clock.Now()), nil
+}
+
+// RequeueDeadLetter moves a dead letter back to the task list and queues it again in place,
+// keeping its ID and recording the failed run in its attempts. The task records the request ID and trace carried by ctx,
+// and the requeue is audited on behalf of its actor. The limits of the owner of the task apply.
+func (m *TaskManager) RequeueDeadLetter(ctx context.Context, id string) (*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, deadExists := m.dead[id]
+	if !deadExists {
+		return nil, fmt.Errorf("cannot requeue dead letter with ID %q: %w", id, ErrDeadLetterNotFound)
+	}
+	if err := m.checkCreate(d.task.Type, d.task.Params, 0); err != nil {
+		return nil, fmt.Errorf("cannot requeue dead letter with ID %q: %w", id, err)
+	}
+	if err := m.checkClient(d.task.Owner, 1); err != nil {
+		return nil, fmt.Errorf("cannot requeue dead letter with ID %q: %w", id, err)
+	}
+
+	if err := m.audit(m.taskAudit(ctx, model.AuditActionRequeue, d.task, d.task.Status, model.TaskStatusPending)); err != nil {
+		return nil, fmt.Errorf("cannot requeue dead letter with ID %q: %w", id, err)
+	}
+
+	now := m.clock.Now()
+	m.chargeClient(d.task.Owner, 1)
+	m.requeueTask(ctx, d.task, now)
+
+	return d.task.Snapshot(now), nil
+}
+
+// DiscardDeadLetter removes a dead letter for good on behalf of the actor carried by ctx.
+func (m *TaskManager) DiscardDeadLetter(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, deadExists := m.dead[id]
+	if !deadExists {
+		return fmt.Errorf("cannot discard dead letter with ID %q: %w", id, ErrDeadLetterNotFound)
+	}
+	if err := m.audit(m.taskAudit(ctx, model.AuditActionDiscard, d.task, d.task.Status, "")); err != nil {
+		return fmt.Errorf("cannot discard dead letter with ID %q: %w", id, err)
+	}
+
+	m.removeDeadLetter(id)
+	m.pruneBatch(d.task.BatchID)
+
+	return nil
+}
+
+// buryTask moves a failed task from the task list to the dead letters of its type,
+// dropping the oldest dead letter of the type beyond the limit, and passes it to the hook.
+// Waiters of the task are woken up, since it will not be in the task list anymore.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) buryTask(t *model.Task, now time.Time) {
+	delete(m.tasks, t.ID)
+	m.markFinished(t.ID)
+	delete(m.finished, t.ID)
+
+	d := &deadLetter{task: t, deadAt: now}
+	m.dead[t.ID] = d
+	m.deadOrder[t.Type] = append(m.deadOrder[t.Type], t.ID)
+
+	if ids := m.deadOrder[t.Type]; len(ids) > m.deadLimit {
+		oldest := m.dead[ids[0]].task
+		m.removeDeadLetter(oldest.ID)
+		m.pruneBatch(oldest.BatchID)
+	}
+
+	m.logTask(slog.LevelInfo, logTaskDeadLetter, t)
+
+	if m.deadHook != nil {
+		m.deadHook(d.snapshot(now))
+	}
+}
+
+// removeDeadLetter forgets the dead letter with the given ID, if any.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) removeDeadLetter(id string) {
+	d, deadExists := m.dead[id]
+	if !deadExists {
+		return
+	}
+
+	delete(m.dead, id)
+
+	ids := slices.DeleteFunc(m.deadOrder[d.task.Type], func(v string) bool { return v == id })
+	if len(ids) == 0 {
+		delete(m.deadOrder, d.task.Type)
+		return
+	}
+	m.deadOrder[d.task.Type] = ids
+}
+
+// snapshot returns the dead letter with a snapshot of its task and the errors of every failed run.
+func (d *deadLetter) snapshot(now time.Time) *model.DeadLetter {
+	result := &model.DeadLetter{Task: d.task.Snapshot(now), DeadAt: d.deadAt, Errors: []model.TaskError{}}
+
+	for i, a := range d.task.Attempts {
+		if a.Status == model.TaskStatusFailed && a.FinishedAt != nil {
+			result.Errors = append(result.Errors, model.TaskError{Attempt: i + 1, FailedAt: *a.FinishedAt, Error: a.Result})
+		}
+	}
+	if d.task.FinishedAt != nil {
+		result.Errors = append(result.Errors, model.TaskError{
+			Attempt:  len(d.task.Attempts) + 1,
+			FailedAt: *d.task.FinishedAt,
+			Error:    d.task.Result,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,145 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+)
+
+type (
+	failingFactory struct{} // failingFactory creates a task that always fails.
+	failingTask    struct{} // failingTask returns an error when run.
+)
+
+// New returns a failing task.
+func (*failingFactory) New(_ *model.Task) task.ExecutableTask {
+	return &failingTask{}
+}
+
+// Run always returns an error.
+func (*failingTask) Run(_ context.Context) error {
+	return errors.New("boom")
+}
+
+// waitForDeadLetter waits until the task is in the dead letters and returns its final state.
+func waitForDeadLetter(t *testing.T, manager *service.TaskManager, id string) *model.DeadLetter {
+	t.Helper()
+	if _, err := manager.WaitTask(context.Background(), id); err != nil {
+		t.Fatalf("WaitTask failed: %v", err)
+	}
+
+	d, err := manager.GetDeadLetter(id)
+	if err != nil {
+		t.Fatalf("GetDeadLetter failed: %v", err)
+	}
+	return d
+}
+
+// TestDeadLetters checks that failed tasks are kept in the dead letters and can still be listed and looked up by ID.
+func TestDeadLetters(t *testing.T) {
+	manager, _ := newFakeManager(service.WithDeadLetters(10, nil))
+	manager.RegisterFactory("fail", &failingFactory{})
+
+	tsk, _ := manager.CreateTask("fail")
+	d := waitForDeadLetter(t, manager, tsk.ID)
+
+	if d.Task.Status != model.TaskStatusFailed || len(d.Errors) != 1 || d.Errors[0].Attempt != 1 {
+		t.Errorf("expected a failed task with one error, got %+v", d)
+	}
+	if tasks := manager.ListTasks(service.TaskFilter{Status: model.TaskStatusFailed}); len(tasks) != 1 || tasks[0].ID != tsk.ID {
+		t.Errorf("expected the dead task to be listed, got %+v", tasks)
+	}
+	if found, err := manager.GetTask(tsk.ID); err != nil || found.Status != model.TaskStatusFailed {
+		t.Errorf("expected the dead task to be found by ID, got %+v, %v", found, err)
+	}
+	if letters := manager.ListDeadLetters("fail"); len(letters) != 1 {
+		t.Errorf("expected one dead letter, got %d", len(letters))
+	}
+}
+
+// TestDeadLetters_Delete checks that failed tasks are deleted from the dead letters, by ID or by filter.
+func TestDeadLetters_Delete(t *testing.T) {
+	manager, _ := newFakeManager(service.WithDeadLetters(10, nil))
+	manager.RegisterFactory("fail", &failingFactory{})
+
+	first, _ := manager.CreateTask("fail")
+	waitForDeadLetter(t, manager, first.ID)
+	second, _ := manager.CreateTask("fail")
+	waitForDeadLetter(t, manager, second.ID)
+
+	if err := manager.DeleteTask(context.Background(), first.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := manager.GetDeadLetter(first.ID); !errors.Is(err, service.ErrDeadLetterNotFound) {
+		t.Errorf("expected the deleted task to leave the dead letters, got %v", err)
+	}
+
+	ids, err := manager.DeleteTasks(context.Background(), service.TaskFilter{Status: model.TaskStatusFailed}, false)
+	if err != nil {
+		t.Fatalf("DeleteTasks failed: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != second.ID {
+		t.Errorf("expected %s to be deleted, got %v", second.ID, ids)
+	}
+	if letters := manager.ListDeadLetters(""); len(letters) != 0 {
+		t.Errorf("expected no dead letter left, got %d", len(letters))
+	}
+	if _, err := manager.GetTask(second.ID); !errors.Is(err, service.ErrTaskNotFound) {
+		t.Errorf("expected the deleted task to be gone, got %v", err)
+	}
+}
+
+// TestDeadLetters_Requeue checks that a requeued dead letter keeps its error history when it fails again.
+func TestDeadLetters_Requeue(t *testing.T) {
+	manager, _ := newFakeManager(service.WithDeadLetters(10, nil))
+	manager.RegisterFactory("fail", &failingFactory{})
+
+	tsk, _ := manager.CreateTask("fail")
+	waitForDeadLetter(t, manager, tsk.ID)
+
+	requeued, err := manager.RequeueDeadLetter(context.Background(), tsk.ID)
+	if err != nil {
+		t.Fatalf("RequeueDeadLetter failed: %v", err)
+	}
+	if requeued.ID != tsk.ID || requeued.Status != model.TaskStatusPending {
+		t.Fatalf("expected the same task to be pending, got %+v", requeued)
+	}
+
+	d := waitForDeadLetter(t, manager, tsk.ID)
+	if len(d.Errors) != 2 || d.Errors[1].Attempt != 2 {
+		t.Errorf("expected errors of both runs, got %+v", d.Errors)
+	}
+}
+
+// TestDeadLetters_LimitAndHook checks that the oldest dead letters are dropped beyond the limit
+// and that every new dead letter reaches the hook.
+func TestDeadLetters_LimitAndHook(t *testing.T) {
+	var exported []string
+	hook := func(d *model.DeadLetter) { exported = append(exported, d.Task.ID) }
+
+	manager, _ := newFakeManager(service.WithDeadLetters(1, hook))
+	manager.RegisterFactory("fail", &failingFactory{})
+
+	first, _ := manager.CreateTask("fail")
+	waitForDeadLetter(t, manager, first.ID)
+	second, _ := manager.CreateTask("fail")
+	waitForDeadLetter(t, manager, second.ID)
+
+	if _, err := manager.GetDeadLetter(first.ID); !errors.Is(err, service.ErrDeadLetterNotFound) {
+		t.Errorf("expected the oldest dead letter to be dropped, got %v", err)
+	}
+	if len(exported) != 2 {
+		t.Errorf("expected both dead letters to be exported, got %v", exported)
+	}
+
+	if err := manager.DiscardDeadLetter(context.Background(), second.ID); err != nil {
+		t.Fatalf("DiscardDeadLetter failed: %v", err)
+	}
+	if _, err := manager.GetTask(second.ID); !errors.Is(err, service.ErrTaskNotFound) {
+		t.Errorf("expected the discarded task to be gone, got %v", err)
+	}
+}
@@ -9,4 +9,19 @@ var (
 	ErrTaskAlreadyExists     = errors.New("task already exists")
 	ErrTaskQueueLimitReached = errors.New("task queue limit reached")
 	ErrTaskUnknownType       = errors.New("task unknown type")
+	ErrTaskTypeDisabled      = errors.New("task type disabled")
+	ErrTaskInvalidConfig     = errors.New("task invalid config")
+	ErrTaskInvalidParams     = errors.New("task invalid params")
+	ErrTaskFinished          = errors.New("task already finished")
+	ErrTaskCanceled          = errors.New("task canceled")
+	ErrTaskManagerClosed     = errors.New("task manager closed")
+	ErrTaskInvalidBatch      = errors.New("task invalid batch")
+	ErrBatchNotFound         = errors.New("batch not found")
+	ErrTaskTypePaused        = errors.New("task type paused")
+	ErrTaskNotFinished       = errors.New("task not finished")
+	ErrDeadLetterNotFound    = errors.New("dead letter not found")
+	ErrClientRateLimited     = errors.New("client rate limited")
+	ErrClientQuotaReached    = errors.New("client pending quota reached")
+	ErrClientBurstExceeded   = errors.New("client burst exceeded")
+	ErrAuditFailed           = errors.New("audit failed")
 )
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// WaitTask blocks until the task reaches a final status or ctx is done, and returns its snapshot.
+// A task deleted while waiting is reported as not found.
+func (m *TaskManager) WaitTask(ctx context.Context, id string) (*model.Task, error) {
+	m.mu.RLock()
+	finished, taskExists := m.finished[id]
+	m.mu.RUnlock()
+
+	if !taskExists {
+		// A task without a wait channel is either unknown or already in the dead letters.
+		return m.GetTask(id)
+	}
+
+	select {
+	case <-finished:
+		return m.GetTask(id)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("cannot wait for task with ID %q: %w", id, ctx.Err())
+	}
+}
+
+// Shutdown stops accepting new tasks and waits for the queued and running ones to finish.
+// If ctx is done first, the remaining tasks are canceled and the context error is returned.
+// Paused queues are resumed so that they can drain. Task types are removed once drained,
+// and the manager cannot be used for new tasks afterwards.
+func (m *TaskManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+
+	var unfinished []chan struct{}
+	for id, finished := range m.finished {
+		if !m.tasks[id].Status.IsFinal() {
+			unfinished = append(unfinished, finished)
+		}
+	}
+	for taskType := range m.factories {
+		m.draining[taskType] = true
+		delete(m.paused, taskType)
+		m.notifyWorker(taskType)
+	}
+	m.mu.Unlock()
+
+	for _, finished := range unfinished {
+		select {
+		case <-finished:
+		case <-ctx.Done():
+			m.cancelAll()
+			return fmt.Errorf("cannot finish tasks before shutdown: %w", ctx.Err())
+		}
+	}
+
+	return nil
+}
+
+// cancelAll cancels every pending and running task.
+func (m *TaskManager) cancelAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, t := range m.tasks {
+		m.cancelTask(t)
+	}
+}
+
+// markFinished wakes up the goroutines waiting for the task.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) markFinished(id string) {
+	if finished, ok := m.finished[id]; ok {
+		select {
+		case <-finished:
+		default:
+			close(finished)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// TestWaitTask ensures waiting returns the final state and respects the context.
+func TestWaitTask(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("delayed", &delayedFactory{clk: fake})
+
+	tsk, _ := manager.CreateTask("delayed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := manager.WaitTask(ctx, tsk.ID); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	fake.BlockUntil(1)
+	fake.Advance(taskDelay)
+
+	got, err := manager.WaitTask(context.Background(), tsk.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != model.TaskStatusDone {
+		t.Errorf("expected status 'done', got %q", got.Status)
+	}
+
+	if _, err := manager.WaitTask(context.Background(), "missing"); !errors.Is(err, service.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+// doneSignalContext is a context that signals the first call of Done,
+// made by Shutdown once the manager is closed and it starts waiting for the tasks.
+type doneSignalContext struct {
+	context.Context
+	once    sync.Once
+	waiting chan struct{}
+}
+
+// Done closes the waiting channel and returns the Done channel of the parent context.
+func (c *doneSignalContext) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.waiting) })
+	return c.Context.Done()
+}
+
+// TestShutdown_Drains ensures shutdown rejects new tasks and waits for queued ones.
+func TestShutdown_Drains(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("delayed", &delayedFactory{clk: fake})
+
+	first, _ := manager.CreateTask("delayed")
+	second, _ := manager.CreateTask("delayed")
+
+	ctx := &doneSignalContext{Context: context.Background(), waiting: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() { done <- manager.Shutdown(ctx) }()
+
+	<-ctx.waiting // Shutdown has closed the manager and waits for the tasks
+	if _, err := manager.CreateTask("delayed"); !errors.Is(err, service.ErrTaskManagerClosed) {
+		t.Errorf("expected ErrTaskManagerClosed, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		fake.BlockUntil(1)
+		fake.Advance(taskDelay)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []string{first.ID, second.ID} {
+		if got, _ := manager.GetTask(id); got.Status != model.TaskStatusDone {
+			t.Errorf("expected task %s to finish, got %q", id, got.Status)
+		}
+	}
+}
+
+// TestShutdown_CancelsOnTimeout ensures tasks left when the context is done are canceled.
+func TestShutdown_CancelsOnTimeout(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("ctx", &ctxFactory{})
+
+	running, _ := manager.CreateTask("ctx")
+	pending, _ := manager.CreateTask("ctx")
+	waitForStatus(t, manager, running.ID, model.TaskStatusRunning)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := manager.Shutdown(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	waitForStatus(t, manager, running.ID, model.TaskStatusCanceled)
+	waitForStatus(t, manager, pending.ID, model.TaskStatusCanceled)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// TaskFilter selects tasks returned by ListTasks. Zero fields match every task.
+type TaskFilter struct {
+	Status        model.TaskStatus // Only tasks with this status
+	Type          string           // Only tasks of this type
+	CreatedBefore time.Time        // Only tasks created before this time
+	Owner         string           // Only tasks of this owner
+}
+
+// Matches reports whether the task satisfies the filter.
+func (f TaskFilter) Matches(t *model.Task) bool {
+	return (f.Status == "" || t.Status == f.Status) && (f.Type == "" || t.Type == f.Type) &&
+		(f.CreatedBefore.IsZero() || t.CreatedAt.Before(f.CreatedBefore)) && (f.Owner == "" || t.Owner == f.Owner)
+}
+
+// ListTasks returns snapshots of the tasks matching the filter, oldest first,
+// including the failed tasks kept in the dead letters.
+func (m *TaskManager) ListTasks(filter TaskFilter) []*model.Task {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.clock.Now()
+
+	tasks := m.matchingTasks(filter)
+	for i, t := range tasks {
+		tasks[i] = t.Snapshot(now)
+	}
+
+	return tasks
+}
+
+// matchingTasks returns the tasks and the dead letter tasks matching the filter, oldest first.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) matchingTasks(filter TaskFilter) []*model.Task {
+	tasks := make([]*model.Task, 0, len(m.tasks))
+	for _, t := range m.tasks {
+		if filter.Matches(t) {
+			tasks = append(tasks, t)
+		}
+	}
+	for _, d := range m.dead {
+		if filter.Matches(d.task) {
+			tasks = append(tasks, d.task)
+		}
+	}
+
+	sortTasks(tasks)
+
+	return tasks
+}
+
+// sortTasks orders tasks oldest first, breaking ties by ID.
+func sortTasks(tasks []*model.Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+}
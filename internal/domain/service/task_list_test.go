@@ -0,0 +1,40 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// TestListTasks checks that tasks are filtered by status and type and listed oldest first.
+func TestListTasks(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("blocked", &blockingFactory{})
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	first, _ := manager.CreateTask("blocked")
+	fake.Advance(time.Second)
+	second, _ := manager.CreateTask("blocked")
+	fake.Advance(time.Second)
+	done, _ := manager.CreateTask("mock")
+
+	waitForStatus(t, manager, first.ID, model.TaskStatusRunning)
+	waitUntilDone(t, manager, done.ID)
+
+	all := manager.ListTasks(service.TaskFilter{})
+	if len(all) != 3 || all[0].ID != first.ID || all[1].ID != second.ID || all[2].ID != done.ID {
+		t.Fatalf("expected all tasks oldest first, got %+v", all)
+	}
+
+	pending := manager.ListTasks(service.TaskFilter{Status: model.TaskStatusPending})
+	if len(pending) != 1 || pending[0].ID != second.ID {
+		t.Errorf("expected only the second task to be pending, got %+v", pending)
+	}
+
+	mock := manager.ListTasks(service.TaskFilter{Type: "mock", Status: model.TaskStatusDone})
+	if len(mock) != 1 || mock[0].ID != done.ID {
+		t.Errorf("expected only the mock task, got %+v", mock)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// Messages of the lifecycle events logged by the manager.
+const (
+	logTaskCreated    = "task created"
+	logTaskRequeued   = "task requeued"
+	logTaskStarted    = "task started"
+	logTaskFinished   = "task finished"
+	logTaskCanceled   = "task canceled"
+	logTaskDeleted    = "task deleted"
+	logTaskDeadLetter = "task moved to dead letters"
+	logTaskOutput     = "task output"
+)
+
+// logOutputLimit is the max number of bytes of the task output written to the debug log.
+const logOutputLimit = 4 * 1024
+
+// logTask logs a lifecycle event of a task with its ID, type, status, attempt, owner,
+// and the ID of the request that queued it, followed by the extra attributes.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) logTask(level slog.Level, msg string, t *model.Task, extra ...slog.Attr) {
+	attrs := make([]slog.Attr, 0, 6+len(extra))
+	attrs = append(attrs,
+		slog.String("task_id", t.ID),
+		slog.String("type", t.Type),
+		slog.String("status", string(t.Status)),
+		slog.Int("attempt", len(t.Attempts)+1),
+	)
+	if t.Owner != "" {
+		attrs = append(attrs, slog.String("owner", t.Owner))
+	}
+	if t.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", t.RequestID))
+	}
+	attrs = append(attrs, extra...)
+
+	m.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// logOutput logs the structured output of a finished task at debug level,
+// cut to logOutputLimit bytes so that a verbose command cannot flood the log.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) logOutput(t *model.Task) {
+	if len(t.Output) == 0 || !m.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	output, truncated := t.Output, len(t.Output) > logOutputLimit
+	if truncated {
+		output = output[:logOutputLimit]
+		for len(output) > 0 && !utf8.Valid(output) {
+			output = output[:len(output)-1] // Do not cut a multibyte character in half
+		}
+	}
+
+	m.logTask(slog.LevelDebug, logTaskOutput, t, slog.String("output", string(output)), slog.Bool("output_truncated", truncated))
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/logging"
+)
+
+// syncBuffer is a buffer safe for the concurrent writes of the workers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write appends to the buffer.
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// records decodes the JSON records written so far.
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var records []map[string]any
+	dec := json.NewDecoder(bytes.NewReader(b.buf.Bytes()))
+	for dec.More() {
+		var r map[string]any
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("cannot decode log record: %v", err)
+		}
+		records = append(records, r)
+	}
+
+	return records
+}
+
+// TestLogger checks the lifecycle events of a task and that they carry the request ID of its creation.
+func TestLogger(t *testing.T) {
+	var buf syncBuffer
+	manager, _ := newFakeManager(service.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	manager.RegisterFactory("mock", &mockFactory{})
+	manager.RegisterFactory("fail", &failingFactory{})
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	done, err := manager.CreateTaskFor(ctx, "alice", "mock", nil)
+	if err != nil {
+		t.Fatalf("CreateTaskFor failed: %v", err)
+	}
+	failed, err := manager.CreateTask("fail")
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	waitUntilDone(t, manager, done.ID)
+	waitUntilDone(t, manager, failed.ID)
+
+	want := map[string][]string{
+		done.ID:   {"task created", "task started", "task finished"},
+		failed.ID: {"task created", "task started", "task finished"},
+	}
+	got := make(map[string][]string)
+	for _, r := range buf.records(t) {
+		id, _ := r["task_id"].(string)
+		got[id] = append(got[id], r["msg"].(string))
+
+		switch {
+		case id == done.ID && (r["request_id"] != "req-1" || r["owner"] != "alice" || r["attempt"] != 1.0):
+			t.Errorf("expected request ID, owner, and attempt in %v", r)
+		case id == failed.ID && r["msg"] == "task finished" && (r["level"] != "WARN" || r["status"] != "failed" || r["error"] == nil):
+			t.Errorf("expected a warning with the error in %v", r)
+		}
+	}
+	for id, msgs := range want {
+		if len(got[id]) != len(msgs) {
+			t.Fatalf("expected events %v for %s, got %v", msgs, id, got[id])
+		}
+		for i := range msgs {
+			if got[id][i] != msgs[i] {
+				t.Errorf("expected events %v for %s, got %v", msgs, id, got[id])
+			}
+		}
+	}
+}
+
+// TestLogger_Output checks that the output of a finished exec task is logged at debug level and capped.
+func TestLogger_Output(t *testing.T) {
+	var buf syncBuffer
+	manager, _ := newFakeManager(service.WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	manager.RegisterFactory(task.ExecTaskType, &task.ExecTaskFactory{
+		AllowedCommands: []string{"echo"},
+		Env:             []string{"PATH=/usr/bin:/bin"},
+	})
+
+	for _, tc := range []struct {
+		arg       string
+		truncated bool
+	}{
+		{arg: "hello", truncated: false},
+		{arg: strings.Repeat("x", 10*1024), truncated: true},
+	} {
+		params, _ := json.Marshal(task.ExecParams{Command: "echo", Args: []string{tc.arg}})
+		created, err := manager.CreateTaskWithParams(task.ExecTaskType, params)
+		if err != nil {
+			t.Fatalf("CreateTaskWithParams failed: %v", err)
+		}
+		waitUntilDone(t, manager, created.ID)
+
+		var record map[string]any
+		for _, r := range buf.records(t) {
+			if r["task_id"] == created.ID && r["msg"] == "task output" {
+				record = r
+			}
+		}
+		if record == nil {
+			t.Fatalf("expected a task output record for %s", created.ID)
+		}
+		output, _ := record["output"].(string)
+		if record["level"] != "DEBUG" || record["output_truncated"] != tc.truncated || len(output) > 4*1024 {
+			t.Errorf("expected a debug record of at most 4 KiB (truncated %v), got %d bytes in %v", tc.truncated, len(output), record["level"])
+		}
+		if !tc.truncated && !strings.Contains(output, `"stdout":"hello\n"`) {
+			t.Errorf("expected the stdout in the output, got %s", output)
+		}
+	}
+}
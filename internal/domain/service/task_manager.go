@@ -1,117 +1,451 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"sync"
 
+	"github.com/kylerqws/task-runner/internal/domain/clock"
 	"github.com/kylerqws/task-runner/internal/domain/model"
 	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/logging"
+	"github.com/kylerqws/task-runner/internal/tracing"
 )
 
 // TaskManager manages task creation, execution, lookup, and deletion.
 type TaskManager struct {
 	mu        sync.RWMutex
-	tasks     map[string]*model.Task   // All tasks by ID
-	factories map[string]task.Factory  // Task type -> factory
-	queues    map[string][]*model.Task // Task type -> task queue
-	active    map[string]int           // Task type -> active count
+	tasks     map[string]*model.Task             // All tasks by ID
+	factories map[string]task.Descriptor         // Task type -> registration descriptor
+	configs   map[string]TypeConfig              // Task type -> queue and execution limits
+	queues    map[string][]*model.Task           // Task type -> task queue
+	active    map[string]int                     // Task type -> active count
+	running   map[string]int                     // Task type -> running count
+	wake      map[string]chan struct{}           // Task type -> worker wake-up signal
+	draining  map[string]bool                    // Task type -> unregistered, finishing queued tasks
+	done      map[string]int                     // Task type -> completed count
+	failed    map[string]int                     // Task type -> failed count
+	canceled  map[string]int                     // Task type -> canceled count
+	cancels   map[string]context.CancelCauseFunc // Running task ID -> cancel function
+	finished  map[string]chan struct{}           // Task ID -> closed once the task reaches a final status
+	batches   map[string]*batch                  // Batch ID -> tasks submitted together
+	paused    map[string]pauseState              // Task type -> paused queue, tasks are not started
+	through   map[string]*throughput             // Task type -> tasks finished over recent windows
+	dead      map[string]*deadLetter             // Dead letter task ID -> failed task moved out of tasks
+	deadOrder map[string][]string                // Task type -> dead letter IDs, oldest first
+	served    map[string]map[string]float64      // Task type -> owner -> tasks started, divided by weight
+	buckets   map[string]*tokenBucket            // Owner -> rate limit of task creation
+	closed    bool                               // Shutdown started, new tasks are rejected
+
+	defaults  TypeConfig      // Limits used by RegisterFactory
+	clock     clock.Clock     // Source of time for timestamps, durations, and timeouts
+	deadLimit int             // Max dead letters per type (0 keeps failed tasks in tasks)
+	deadHook  DeadLetterHook  // Receives every new dead letter (optional)
+	logger    *slog.Logger    // Receives the lifecycle events of tasks
+	tracer    *tracing.Tracer // Records the queue wait and run of tasks (optional)
+	auditor   Auditor         // Records state-changing operations (optional)
+
+	clientDefaults  ClientLimits            // Limits of every client
+	clientOverrides map[string]ClientLimits // Owner -> limits overriding the defaults
 }
 
-// NewTaskManager returns a new instance with empty internal maps.
-func NewTaskManager() *TaskManager {
-	return &TaskManager{
+// NewTaskManager returns a new instance with empty internal maps,
+// configured with the given options.
+func NewTaskManager(opts ...Option) *TaskManager {
+	m := &TaskManager{
 		tasks:     make(map[string]*model.Task),
-		factories: make(map[string]task.Factory),
+		factories: make(map[string]task.Descriptor),
+		configs:   make(map[string]TypeConfig),
 		queues:    make(map[string][]*model.Task),
 		active:    make(map[string]int),
+		running:   make(map[string]int),
+		wake:      make(map[string]chan struct{}),
+		draining:  make(map[string]bool),
+		done:      make(map[string]int),
+		failed:    make(map[string]int),
+		canceled:  make(map[string]int),
+		cancels:   make(map[string]context.CancelCauseFunc),
+		finished:  make(map[string]chan struct{}),
+		batches:   make(map[string]*batch),
+		paused:    make(map[string]pauseState),
+		through:   make(map[string]*throughput),
+		dead:      make(map[string]*deadLetter),
+		deadOrder: make(map[string][]string),
+		served:    make(map[string]map[string]float64),
+		buckets:   make(map[string]*tokenBucket),
+
+		defaults: DefaultTypeConfig(),
+		clock:    clock.Real(),
+		logger:   slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
+}
+
+// Clock returns the clock used by the manager, so that task factories can share it.
+func (m *TaskManager) Clock() clock.Clock {
+	return m.clock
 }
 
-// RegisterFactory sets up a task type with its factory and starts the worker.
+// RegisterFactory sets up a task type with its factory and the default limits.
 func (m *TaskManager) RegisterFactory(taskType string, factory task.Factory) {
+	m.RegisterFactoryWithConfig(taskType, factory, m.defaults)
+}
+
+// RegisterFactoryWithConfig sets up a task type with its factory and limits.
+func (m *TaskManager) RegisterFactoryWithConfig(taskType string, factory task.Factory, cfg TypeConfig) {
+	m.Register(task.Descriptor{Type: taskType, Factory: factory}, cfg)
+}
+
+// Register sets up a task type from its descriptor and limits, and starts the worker.
+// Registering a type that is still draining after UnregisterFactory re-enables it with the new descriptor.
+// It has no effect once Shutdown has been called.
+func (m *TaskManager) Register(desc task.Descriptor, cfg TypeConfig) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.closed {
+		return
+	}
+
+	m.register(desc, cfg)
+}
+
+// register is Register with the manager already locked.
+// WARNING: Must be called with m.mu.Lock held, before Shutdown.
+func (m *TaskManager) register(desc task.Descriptor, cfg TypeConfig) {
+	taskType := desc.Type
+
+	if m.draining[taskType] {
+		delete(m.draining, taskType)
+		m.factories[taskType] = desc
+		m.configs[taskType] = cfg.normalize()
+		m.notifyWorker(taskType)
+		return
+	}
+
 	if _, ok := m.factories[taskType]; !ok {
-		m.factories[taskType] = factory
+		m.factories[taskType] = desc
+		m.configs[taskType] = cfg.normalize()
 		m.queues[taskType] = []*model.Task{}
+		m.wake[taskType] = make(chan struct{}, 1)
+		m.through[taskType] = &throughput{}
+		m.served[taskType] = make(map[string]float64)
 		go m.workerLoop(taskType)
 	}
 }
 
-// CreateTask adds a new task to the queue if the type is known and not full.
+// UnregisterFactory stops accepting new tasks of the given type.
+// Tasks already queued are still executed; the type is removed once its queue is drained.
+func (m *TaskManager) UnregisterFactory(taskType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.factories[taskType]; !ok || m.draining[taskType] {
+		return fmt.Errorf("cannot unregister task type %q: %w", taskType, ErrTaskUnknownType)
+	}
+
+	m.draining[taskType] = true
+	m.notifyWorker(taskType)
+
+	return nil
+}
+
+// UpdateTypeConfig replaces the limits of a registered task type in one step.
+// Queued and running tasks are kept; the new limits apply to tasks started afterwards.
+func (m *TaskManager) UpdateTypeConfig(taskType string, cfg TypeConfig) error {
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("cannot update task type %q: %w", taskType, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.factories[taskType]; !ok || m.draining[taskType] {
+		return fmt.Errorf("cannot update task type %q: %w", taskType, ErrTaskUnknownType)
+	}
+
+	m.configs[taskType] = cfg
+	m.notifyWorker(taskType)
+
+	return nil
+}
+
+// TypeUpdate is the new registration of a task type applied by ApplyTypes.
+type TypeUpdate struct {
+	Descriptor task.Descriptor // Descriptor of the type (only Type is used if Disabled)
+	Config     TypeConfig      // Queue and execution limits
+	Disabled   bool            // Stop accepting new tasks of the type, as UnregisterFactory does
+}
+
+// ApplyTypes registers, updates, or disables several task types in one step:
+// either every update is applied, or none is if any limits are invalid.
+// Registered types get the new descriptor and limits, including the factory used for new tasks
+// and for queued tasks started afterwards; disabled types keep finishing their queued tasks.
+func (m *TaskManager) ApplyTypes(updates []TypeUpdate) error {
+	for _, u := range updates {
+		if u.Disabled {
+			continue
+		}
+		if err := u.Config.validate(); err != nil {
+			return fmt.Errorf("cannot update task type %q: %w", u.Descriptor.Type, err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return fmt.Errorf("cannot update task types: %w", ErrTaskManagerClosed)
+	}
+
+	for _, u := range updates {
+		taskType := u.Descriptor.Type
+		_, registered := m.factories[taskType]
+
+		switch {
+		case u.Disabled:
+			if registered && !m.draining[taskType] {
+				m.draining[taskType] = true
+				m.notifyWorker(taskType)
+			}
+		case registered && !m.draining[taskType]:
+			m.factories[taskType] = u.Descriptor
+			m.configs[taskType] = u.Config
+			m.notifyWorker(taskType)
+		default:
+			m.register(u.Descriptor, u.Config)
+		}
+	}
+
+	return nil
+}
+
+// CreateTask adds a new task without parameters to the queue if the type is known and not full.
 func (m *TaskManager) CreateTask(taskType string) (*model.Task, error) {
-	if taskType == "" {
-		return nil, fmt.Errorf("cannot create task: %w", ErrTaskUnknownType)
+	return m.CreateTaskWithParams(taskType, nil)
+}
+
+// CreateTaskWithParams adds a new task with the given parameters to the queue
+// if the type is known, the parameters are accepted by its factory, and the queue is not full.
+func (m *TaskManager) CreateTaskWithParams(taskType string, params json.RawMessage) (*model.Task, error) {
+	return m.CreateTaskFor(context.Background(), "", taskType, params)
+}
+
+// CreateTaskFor is like CreateTaskWithParams, and records the given owner (client identity) in the task,
+// along with the request ID (see logging.WithRequestID) and the trace (see tracing.ContextWithSpan) carried by ctx.
+// The limits of the owner apply; exceeding them returns a *ClientLimitError.
+func (m *TaskManager) CreateTaskFor(ctx context.Context, owner, taskType string, params json.RawMessage) (*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkCreate(taskType, params, 0); err != nil {
+		return nil, err
+	}
+	if err := m.checkClient(owner, 1); err != nil {
+		return nil, err
 	}
 
-	m.mu.RLock()
-	_, typeExists := m.factories[taskType]
-	activeCount := m.active[taskType]
-	m.mu.RUnlock()
+	t, err := m.newTask(ctx, owner, taskType, params, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := m.audit(m.taskAudit(ctx, model.AuditActionCreate, t, "", t.Status)); err != nil {
+		return nil, fmt.Errorf("cannot create task with type %q: %w", taskType, err)
+	}
 
-	if !typeExists {
-		return nil, fmt.Errorf("cannot create task with type %q: %w", taskType, ErrTaskUnknownType)
+	m.chargeClient(owner, 1)
+	m.addTask(t)
+
+	return t.Snapshot(m.clock.Now()), nil
+}
+
+// checkCreate returns an error unless a task of the given type and parameters can be queued
+// in addition to the given number of tasks of the same type about to be queued.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) checkCreate(taskType string, params json.RawMessage, extra int) error {
+	if taskType == "" {
+		return fmt.Errorf("cannot create task: %w", ErrTaskUnknownType)
 	}
-	if activeCount >= taskQueueBufferSize {
-		return nil, fmt.Errorf("cannot create task with type %q: %w", taskType, ErrTaskQueueLimitReached)
+	if m.closed {
+		return fmt.Errorf("cannot create task with type %q: %w", taskType, ErrTaskManagerClosed)
 	}
 
-	id := m.generateID()
+	desc, typeExists := m.factories[taskType]
+	if !typeExists {
+		return fmt.Errorf("cannot create task with type %q: %w", taskType, ErrTaskUnknownType)
+	}
+	if m.draining[taskType] {
+		return fmt.Errorf("cannot create task with type %q: %w", taskType, ErrTaskTypeDisabled)
+	}
+	if m.paused[taskType].rejectNew {
+		return fmt.Errorf("cannot create task with type %q: %w", taskType, ErrTaskTypePaused)
+	}
+	if m.active[taskType]+extra >= m.configs[taskType].QueueSize {
+		return fmt.Errorf("cannot create task with type %q: %w", taskType, ErrTaskQueueLimitReached)
+	}
+	if v, ok := desc.Factory.(task.ParamsValidator); ok {
+		if err := v.ValidateParams(params); err != nil {
+			return fmt.Errorf("cannot create task with type %q: %w: %v", taskType, ErrTaskInvalidParams, err)
+		}
+	}
 
-	m.mu.RLock()
-	_, taskExists := m.tasks[id]
-	m.mu.RUnlock()
+	return nil
+}
 
-	if taskExists {
+// newTask returns a pending task with a new ID, not queued yet.
+// The request ID and the trace carried by ctx are recorded in the task.
+// WARNING: Must be called with m.mu held, after checkCreate.
+func (m *TaskManager) newTask(ctx context.Context, owner, taskType string, params json.RawMessage, batchID string) (*model.Task, error) {
+	id := m.generateID()
+	if _, taskExists := m.tasks[id]; taskExists {
 		return nil, fmt.Errorf("cannot create task with ID %q: %w", id, ErrTaskAlreadyExists)
 	}
 
-	t := model.NewTask(id, taskType)
+	t := model.NewTask(id, taskType, m.clock.Now())
+	t.Params = params
+	t.BatchID = batchID
+	t.Owner = owner
+	t.RequestID = logging.RequestID(ctx)
+	t.TraceParent = tracing.SpanContextFromContext(ctx).Traceparent()
 
-	m.mu.Lock()
+	return t, nil
+}
+
+// addTask adds a task returned by newTask to the queue of its type.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) addTask(t *model.Task) {
 	m.tasks[t.ID] = t
+	m.finished[t.ID] = make(chan struct{})
 	m.enqueueTask(t)
-	m.mu.Unlock()
 
-	return t, nil
+	if t.BatchID != "" {
+		m.logTask(slog.LevelInfo, logTaskCreated, t, slog.String("batch_id", t.BatchID))
+	} else {
+		m.logTask(slog.LevelInfo, logTaskCreated, t)
+	}
 }
 
-// GetTask returns a task by ID or an error if not found.
+// GetTask returns a snapshot of a task by ID or an error if not found.
+// Tasks moved to the dead letters are found as well.
+// The snapshot is not updated afterwards; its durations are derived at the time of the call.
 func (m *TaskManager) GetTask(id string) (*model.Task, error) {
 	m.mu.RLock()
-	t, taskExists := m.tasks[id]
-	m.mu.RUnlock()
+	defer m.mu.RUnlock()
 
+	t, taskExists := m.findTask(id)
 	if !taskExists {
 		return nil, fmt.Errorf("cannot find task with ID %q: %w", id, ErrTaskNotFound)
 	}
-	return t, nil
+	return t.Snapshot(m.clock.Now()), nil
 }
 
-// DeleteTask removes a task if it's not running.
-func (m *TaskManager) DeleteTask(id string) error {
-	m.mu.RLock()
-	t, taskExists := m.tasks[id]
-	m.mu.RUnlock()
+// findTask returns a task by ID, looking into the dead letters as well.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) findTask(id string) (*model.Task, bool) {
+	if t, taskExists := m.tasks[id]; taskExists {
+		return t, true
+	}
+	if d, deadExists := m.dead[id]; deadExists {
+		return d.task, true
+	}
+
+	return nil, false
+}
+
+// DeleteTask removes a task if it's not running, on behalf of the actor carried by ctx.
+// A failed task is removed from the dead letters.
+func (m *TaskManager) DeleteTask(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
+	t, taskExists := m.findTask(id)
 	if !taskExists {
 		return fmt.Errorf("cannot delete task with ID %q: %w", id, ErrTaskNotFound)
 	}
 	if t.Status == model.TaskStatusRunning {
 		return fmt.Errorf("cannot delete task with ID %q: %w", id, ErrTaskInProgress)
 	}
+	if err := m.audit(m.taskAudit(ctx, model.AuditActionDelete, t, t.Status, "")); err != nil {
+		return fmt.Errorf("cannot delete task with ID %q: %w", id, err)
+	}
 
-	m.mu.Lock()
-	delete(m.tasks, id)
+	m.deleteTask(t)
+
+	return nil
+}
+
+// deleteTask removes a task that is not running, dropping it from the queue if it is still pending
+// or from the dead letters if it failed.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) deleteTask(t *model.Task) {
+	delete(m.tasks, t.ID)
+	m.removeDeadLetter(t.ID)
 	m.removeFromQueue(t)
-	m.mu.Unlock()
+	m.markFinished(t.ID)
+	delete(m.finished, t.ID)
+	m.pruneBatch(t.BatchID)
+
+	m.logTask(slog.LevelInfo, logTaskDeleted, t)
+}
+
+// CancelTask cancels a pending or running task on behalf of the actor carried by ctx.
+// A pending task is removed from the queue; a running task has its context cancelled
+// and is marked as canceled once it returns.
+func (m *TaskManager) CancelTask(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, taskExists := m.tasks[id]
+	if !taskExists {
+		return fmt.Errorf("cannot cancel task with ID %q: %w", id, ErrTaskNotFound)
+	}
+	if t.Status.IsFinal() {
+		return fmt.Errorf("cannot cancel task with ID %q: %w", id, ErrTaskFinished)
+	}
+
+	// A running task is only marked as canceled once it returns.
+	after := model.TaskStatusCanceled
+	if t.Status == model.TaskStatusRunning {
+		after = t.Status
+	}
+	if err := m.audit(m.taskAudit(ctx, model.AuditActionCancel, t, t.Status, after)); err != nil {
+		return fmt.Errorf("cannot cancel task with ID %q: %w", id, err)
+	}
+
+	m.cancelTask(t)
 
 	return nil
 }
 
+// cancelTask marks a pending task as canceled or cancels the context of a running one.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) cancelTask(t *model.Task) {
+	switch t.Status {
+	case model.TaskStatusPending:
+		now := m.clock.Now()
+		m.removeFromQueue(t)
+		t.FinishedAt = &now
+		m.canceled[t.Type]++
+		t.Status = model.TaskStatusCanceled
+		t.Result = "Task canceled"
+		m.markFinished(t.ID)
+		m.logTask(slog.LevelInfo, logTaskCanceled, t)
+	case model.TaskStatusRunning:
+		if cancel, ok := m.cancels[t.ID]; ok {
+			cancel(ErrTaskCanceled)
+		}
+	}
+}
+
 // generateID returns a secure random 128-bit hex string.
 func (m *TaskManager) generateID() string {
 	b := make([]byte, 16)
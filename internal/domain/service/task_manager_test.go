@@ -1,6 +1,8 @@
 package service_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -20,7 +22,7 @@ func (*mockFactory) New(_ *model.Task) task.ExecutableTask {
 }
 
 // Run simulates success.
-func (*mockTask) Run() error {
+func (*mockTask) Run(_ context.Context) error {
 	return nil
 }
 
@@ -106,9 +108,9 @@ func TestDeleteTask(t *testing.T) {
 	manager := service.NewTaskManager()
 	manager.RegisterFactory("mock", &mockFactory{})
 	tsk, _ := manager.CreateTask("mock")
-	tsk.Status = model.TaskStatusDone
+	waitUntilDone(t, manager, tsk.ID)
 
-	err := manager.DeleteTask(tsk.ID)
+	err := manager.DeleteTask(context.Background(), tsk.ID)
 	if err != nil {
 		t.Errorf("expected task to be deleted, got error: %v", err)
 	}
@@ -121,11 +123,11 @@ func TestDeleteTask(t *testing.T) {
 // TestDeleteTask_Running verifies that running tasks cannot be deleted.
 func TestDeleteTask_Running(t *testing.T) {
 	manager := service.NewTaskManager()
-	manager.RegisterFactory("mock", &mockFactory{})
-	tsk, _ := manager.CreateTask("mock")
-	tsk.Status = model.TaskStatusRunning
+	manager.RegisterFactory("blocked", &blockingFactory{})
+	tsk, _ := manager.CreateTask("blocked")
+	waitForStatus(t, manager, tsk.ID, model.TaskStatusRunning)
 
-	err := manager.DeleteTask(tsk.ID)
+	err := manager.DeleteTask(context.Background(), tsk.ID)
 	if err == nil {
 		t.Fatal("expected error for running task")
 	}
@@ -133,3 +135,130 @@ func TestDeleteTask_Running(t *testing.T) {
 		t.Errorf("expected ErrTaskInProgress, got %v", err)
 	}
 }
+
+// TestUnregisterFactory_RejectsNewTasks ensures an unregistered type refuses new tasks
+// but keeps the queued ones.
+func TestUnregisterFactory_RejectsNewTasks(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+	tsk, _ := manager.CreateTask("mock")
+
+	if err := manager.UnregisterFactory("mock"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := manager.CreateTask("mock")
+	if !errors.Is(err, service.ErrTaskTypeDisabled) && !errors.Is(err, service.ErrTaskUnknownType) {
+		t.Errorf("expected ErrTaskTypeDisabled or ErrTaskUnknownType, got %v", err)
+	}
+	if _, err := manager.GetTask(tsk.ID); err != nil {
+		t.Errorf("expected queued task to be kept, got error: %v", err)
+	}
+}
+
+// TestUnregisterFactory_UnknownType ensures unregistering an unknown type fails.
+func TestUnregisterFactory_UnknownType(t *testing.T) {
+	manager := service.NewTaskManager()
+	err := manager.UnregisterFactory("unknown")
+	if !errors.Is(err, service.ErrTaskUnknownType) {
+		t.Errorf("expected ErrTaskUnknownType, got %v", err)
+	}
+}
+
+// TestUpdateTypeConfig verifies that new limits are applied and invalid ones rejected.
+func TestUpdateTypeConfig(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	err := manager.UpdateTypeConfig("mock", service.TypeConfig{QueueSize: 0, Concurrency: 1})
+	if !errors.Is(err, service.ErrTaskInvalidConfig) {
+		t.Errorf("expected ErrTaskInvalidConfig, got %v", err)
+	}
+
+	err = manager.UpdateTypeConfig("unknown", service.TypeConfig{QueueSize: 1, Concurrency: 1})
+	if !errors.Is(err, service.ErrTaskUnknownType) {
+		t.Errorf("expected ErrTaskUnknownType, got %v", err)
+	}
+
+	if err := manager.UpdateTypeConfig("mock", service.TypeConfig{QueueSize: 1, Concurrency: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestApplyTypes verifies that types are updated, registered, and disabled together, or not at all.
+func TestApplyTypes(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory(task.ExecTaskType, &task.ExecTaskFactory{AllowedCommands: []string{"echo", "true"}})
+	manager.RegisterFactory("old", &mockFactory{})
+
+	narrow := task.Descriptor{Type: task.ExecTaskType, Factory: &task.ExecTaskFactory{AllowedCommands: []string{"true"}}}
+	valid := service.TypeConfig{QueueSize: 10, Concurrency: 1}
+
+	err := manager.ApplyTypes([]service.TypeUpdate{
+		{Descriptor: narrow, Config: valid},
+		{Descriptor: task.Descriptor{Type: "new", Factory: &mockFactory{}}, Config: service.TypeConfig{}},
+	})
+	if !errors.Is(err, service.ErrTaskInvalidConfig) {
+		t.Fatalf("expected ErrTaskInvalidConfig, got %v", err)
+	}
+	if _, err := manager.CreateTaskWithParams(task.ExecTaskType, json.RawMessage(`{"command":"echo"}`)); err != nil {
+		t.Errorf("expected the factory to be kept after a failed update, got %v", err)
+	}
+
+	err = manager.ApplyTypes([]service.TypeUpdate{
+		{Descriptor: narrow, Config: valid},
+		{Descriptor: task.Descriptor{Type: "new", Factory: &mockFactory{}}, Config: valid},
+		{Descriptor: task.Descriptor{Type: "old"}, Disabled: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.CreateTaskWithParams(task.ExecTaskType, json.RawMessage(`{"command":"echo"}`)); !errors.Is(err, service.ErrTaskInvalidParams) {
+		t.Errorf("expected ErrTaskInvalidParams, got %v", err)
+	}
+	if _, err := manager.CreateTask("new"); err != nil {
+		t.Errorf("expected the new type to be registered, got %v", err)
+	}
+	if _, err := manager.CreateTask("old"); !errors.Is(err, service.ErrTaskTypeDisabled) && !errors.Is(err, service.ErrTaskUnknownType) {
+		t.Errorf("expected the old type to be disabled, got %v", err)
+	}
+}
+
+// TestCancelTask_Pending ensures a pending task is removed from the queue and marked as canceled.
+func TestCancelTask_Pending(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("blocked", &blockingFactory{})
+	_, _ = manager.CreateTask("blocked")
+	tsk, _ := manager.CreateTask("blocked")
+
+	if err := manager.CancelTask(context.Background(), tsk.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, _ := manager.GetTask(tsk.ID)
+	if found.Status != model.TaskStatusCanceled {
+		t.Errorf("expected status 'canceled', got %q", found.Status)
+	}
+	if err := manager.CancelTask(context.Background(), tsk.ID); !errors.Is(err, service.ErrTaskFinished) {
+		t.Errorf("expected ErrTaskFinished, got %v", err)
+	}
+}
+
+// TestCancelTask_NotFound checks that cancelling an unknown task returns an error.
+func TestCancelTask_NotFound(t *testing.T) {
+	manager := service.NewTaskManager()
+	if err := manager.CancelTask(context.Background(), "non-existent"); !errors.Is(err, service.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+// TestCreateTaskWithParams_Invalid ensures parameters rejected by the factory fail creation.
+func TestCreateTaskWithParams_Invalid(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory(task.ExecTaskType, &task.ExecTaskFactory{AllowedCommands: []string{"echo"}})
+
+	_, err := manager.CreateTaskWithParams(task.ExecTaskType, json.RawMessage(`{"command":"rm"}`))
+	if !errors.Is(err, service.ErrTaskInvalidParams) {
+		t.Errorf("expected ErrTaskInvalidParams, got %v", err)
+	}
+}
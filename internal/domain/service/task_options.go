@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/clock"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// TypeConfig holds queue and execution limits of a single task type.
+type TypeConfig struct {
+	QueueSize   int           // Max number of pending and running tasks
+	Concurrency int           // Max number of tasks running at once
+	Timeout     time.Duration // Max execution time per task (0 means no limit)
+}
+
+// Option configures a TaskManager.
+type Option func(*TaskManager)
+
+// DefaultTypeConfig returns the limits used for task types without explicit config.
+func DefaultTypeConfig() TypeConfig {
+	return TypeConfig{QueueSize: taskQueueBufferSize, Concurrency: 1}
+}
+
+// WithDefaultTypeConfig sets the limits used by RegisterFactory.
+func WithDefaultTypeConfig(cfg TypeConfig) Option {
+	return func(m *TaskManager) {
+		m.defaults = cfg.normalize()
+	}
+}
+
+// validate checks that the limits are usable as they are.
+func (c TypeConfig) validate() error {
+	if c.QueueSize < 1 || c.Concurrency < 1 || c.Timeout < 0 {
+		return fmt.Errorf("%w: %+v", ErrTaskInvalidConfig, c)
+	}
+
+	return nil
+}
+
+// DeadLetterHook is called with every task moved to the dead letters, e.g. to export it.
+// It is called with the manager locked and must not call back into the manager.
+type DeadLetterHook func(*model.DeadLetter)
+
+// WithDeadLetters moves failed tasks out of the task list into per-type dead letters,
+// keeping at most limit of them per type (the oldest are dropped first). The hook (optional)
+// receives every new dead letter. A limit below 1 keeps failed tasks in the task list.
+func WithDeadLetters(limit int, hook DeadLetterHook) Option {
+	return func(m *TaskManager) {
+		m.deadLimit = max(limit, 0)
+		m.deadHook = hook
+	}
+}
+
+// WithClientLimits sets the default limits of clients and their per-client overrides (see SetClientLimits).
+func WithClientLimits(defaults ClientLimits, overrides map[string]ClientLimits) Option {
+	return func(m *TaskManager) {
+		m.setClientLimits(defaults, overrides)
+	}
+}
+
+// WithLogger sets the logger receiving the lifecycle events of tasks: creation, start, completion,
+// cancellation, deletion, retries, and moves to the dead letters. Events are discarded by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *TaskManager) {
+		if logger != nil {
+			m.logger = logger
+		}
+	}
+}
+
+// WithTracer records a span for the queue wait and one for the run of every task,
+// continuing the trace of the request that queued it. Tasks are not traced by default.
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(m *TaskManager) {
+		m.tracer = tracer
+	}
+}
+
+// Auditor records the entries of state-changing operations, e.g. to an audit log.
+// It is called with the manager locked, before the operation is applied, and must not call back into the manager.
+// An operation is not applied if its entries cannot be recorded.
+type Auditor interface {
+	Record(entries ...*model.AuditEntry) error
+}
+
+// WithAuditor records every create, delete, cancel, retry, requeue, discard, purge, pause, and resume
+// of tasks and queues with the auditor, on behalf of the actor carried by the context (see audit.WithActor).
+// Operations are not audited by default.
+func WithAuditor(auditor Auditor) Option {
+	return func(m *TaskManager) {
+		m.auditor = auditor
+	}
+}
+
+// WithClock sets the clock used for timestamps, durations, and timeouts.
+func WithClock(c clock.Clock) Option {
+	return func(m *TaskManager) {
+		if c != nil {
+			m.clock = c
+		}
+	}
+}
+
+// normalize replaces invalid limits with the built-in defaults.
+func (c TypeConfig) normalize() TypeConfig {
+	if c.QueueSize < 1 {
+		c.QueueSize = taskQueueBufferSize
+	}
+	if c.Concurrency < 1 {
+		c.Concurrency = 1
+	}
+	if c.Timeout < 0 {
+		c.Timeout = 0
+	}
+
+	return c
+}
@@ -1,106 +1,189 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/kylerqws/task-runner/internal/domain/clock"
 	"github.com/kylerqws/task-runner/internal/domain/model"
 	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/tracing"
 )
 
-const (
-	taskQueueBufferSize        = 100                    // Max number of tasks in the queue
-	taskDurationUpdateInterval = 500 * time.Millisecond // Duration update interval
-)
+const taskQueueBufferSize = 100 // Default max number of tasks in the queue
 
-// workerLoop processes tasks from the queue in order for a given type.
+// workerLoop starts queued tasks for a given type, taking turns between clients,
+// and keeps at most the configured number of them running at once.
 func (m *TaskManager) workerLoop(taskType string) {
-	for {
+	m.mu.RLock()
+	wake := m.wake[taskType]
+	m.mu.RUnlock()
+
+	for range wake {
 		m.mu.Lock()
-		queue := m.queues[taskType]
-		factory := m.factories[taskType]
+		_, paused := m.paused[taskType]
+		for !paused && m.running[taskType] < m.configs[taskType].Concurrency && len(m.queues[taskType]) > 0 {
+			m.startTask(m.dequeueTask(taskType))
+		}
 
-		if len(queue) == 0 {
+		if m.draining[taskType] && len(m.queues[taskType]) == 0 && m.running[taskType] == 0 {
+			m.removeType(taskType)
 			m.mu.Unlock()
-			time.Sleep(100 * time.Millisecond)
-			continue
+			return
 		}
-
-		t := queue[0]
-		m.queues[taskType] = queue[1:]
 		m.mu.Unlock()
+	}
+}
 
-		exec := factory.New(t)
-		m.runExecutableTask(t, exec)
+// removeType forgets a drained task type so that it can be registered again from scratch.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) removeType(taskType string) {
+	delete(m.factories, taskType)
+	delete(m.configs, taskType)
+	delete(m.queues, taskType)
+	delete(m.active, taskType)
+	delete(m.running, taskType)
+	delete(m.wake, taskType)
+	delete(m.draining, taskType)
+	delete(m.done, taskType)
+	delete(m.failed, taskType)
+	delete(m.canceled, taskType)
+	delete(m.paused, taskType)
+	delete(m.through, taskType)
+	delete(m.served, taskType)
+}
 
-		m.mu.Lock()
-		m.active[t.Type]--
-		m.mu.Unlock()
+// notifyWorker wakes up the worker of the given type without blocking.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) notifyWorker(taskType string) {
+	select {
+	case m.wake[taskType] <- struct{}{}:
+	default:
 	}
 }
 
-// runExecutableTask runs the task and finalizes its result.
-func (m *TaskManager) runExecutableTask(t *model.Task, exec task.ExecutableTask) {
+// startTask marks a dequeued task as running and executes it in a separate goroutine.
+// WARNING: Must be called with m.mu.Lock held.
+func (m *TaskManager) startTask(t *model.Task) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	now := m.clock.Now()
+
+	m.running[t.Type]++
+	m.cancels[t.ID] = cancel
 	t.Status = model.TaskStatusRunning
+	t.StartedAt = &now
 
-	start := time.Now()
-	stop := m.trackDuration(t, start)
+	exec := m.factories[t.Type].Factory.New(t)
+	timeout := m.configs[t.Type].Timeout
 
-	err := exec.Run()
-	stop()
+	m.logTask(slog.LevelInfo, logTaskStarted, t, slog.Duration("queued", now.Sub(t.CreatedAt)))
+	ctx = m.startSpans(ctx, t, now)
 
-	m.finalizeTask(t, err)
+	go m.runExecutableTask(ctx, t, exec, timeout)
 }
 
-// trackDuration updates task duration while it's running.
-func (m *TaskManager) trackDuration(t *model.Task, start time.Time) func() {
-	ticker := time.NewTicker(taskDurationUpdateInterval)
-	done := make(chan struct{})
-
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				m.updateDuration(t, start)
-			case <-done:
-				m.updateDuration(t, start)
-				ticker.Stop()
-				return
-			}
-		}
-	}()
+// runExecutableTask runs the task within its timeout and finalizes its result.
+func (m *TaskManager) runExecutableTask(ctx context.Context, t *model.Task, exec task.ExecutableTask, timeout time.Duration) {
+	span := tracing.SpanFromContext(ctx)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, m.clock, timeout)
+		defer cancel()
+	}
+
+	err := exec.Run(ctx)
+	switch {
+	case errors.Is(context.Cause(ctx), ErrTaskCanceled):
+		err = ErrTaskCanceled
+	case errors.Is(context.Cause(ctx), context.DeadlineExceeded):
+		err = fmt.Errorf("timed out after %s: %w", timeout, context.DeadlineExceeded)
+	}
 
-	return func() { close(done) }
+	m.finalizeTask(t, exec, err, span)
 }
 
-// finalizeTask sets task status and result after execution.
-func (m *TaskManager) finalizeTask(t *model.Task, err error) {
+// finalizeTask sets task status, result, and output after execution, frees its slot, and ends its run span.
+func (m *TaskManager) finalizeTask(t *model.Task, exec task.ExecutableTask, err error, span *tracing.Span) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	defer m.markFinished(t.ID)
+
+	if cancel, ok := m.cancels[t.ID]; ok {
+		cancel(nil)
+		delete(m.cancels, t.ID)
+	}
+
+	now := m.clock.Now()
+	defer func() { m.through[t.Type].record(now, t.Status) }()
+	defer endRunSpan(span, t, now)
+
+	m.running[t.Type]--
+	m.active[t.Type]--
+	m.notifyWorker(t.Type)
+
+	t.FinishedAt = &now
+
+	if out, ok := exec.(task.OutputTask); ok {
+		if data, mErr := json.Marshal(out.Output()); mErr == nil {
+			t.Output = data
+		}
+	}
+
+	if errors.Is(err, ErrTaskCanceled) {
+		m.canceled[t.Type]++
+		t.Status = model.TaskStatusCanceled
+		t.Result = "Task canceled"
+		m.logFinished(t, now, err)
+		return
+	}
 
 	if err != nil {
+		m.failed[t.Type]++
 		t.Status = model.TaskStatusFailed
 		t.Result = fmt.Sprintf("Task execution failed: %v", err)
+		m.logFinished(t, now, err)
+
+		if m.deadLimit > 0 {
+			m.buryTask(t, now)
+		}
 		return
 	}
 
+	m.done[t.Type]++
 	t.Status = model.TaskStatusDone
 	t.Result = "Task completed successfully"
+	m.logFinished(t, now, nil)
 }
 
-// updateDuration sets how long the task has been running.
-func (m *TaskManager) updateDuration(t *model.Task, start time.Time) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// logFinished logs the completion of a task with its run duration, at warning level if it failed,
+// and its output at debug level.
+// WARNING: Must be called with m.mu held, after the final status is set.
+func (m *TaskManager) logFinished(t *model.Task, now time.Time, err error) {
+	var duration time.Duration
+	if t.StartedAt != nil {
+		duration = now.Sub(*t.StartedAt)
+	}
+	defer m.logOutput(t)
 
-	t.Duration = time.Since(start).Truncate(time.Second).String()
+	if t.Status == model.TaskStatusFailed {
+		m.logTask(slog.LevelWarn, logTaskFinished, t, slog.Duration("duration", duration), slog.String("error", err.Error()))
+		return
+	}
+	m.logTask(slog.LevelInfo, logTaskFinished, t, slog.Duration("duration", duration))
 }
 
 // enqueueTask adds a task to the queue and updates the counter.
 // WARNING: Must be called with m.mu.Lock held.
 func (m *TaskManager) enqueueTask(t *model.Task) {
+	m.joinFairQueue(t)
 	m.queues[t.Type] = append(m.queues[t.Type], t)
 	m.active[t.Type]++
+	m.notifyWorker(t.Type)
 }
 
 // removeFromQueue deletes a task from the queue and updates the counter.
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// pauseState records when a queue was paused and whether new tasks are rejected meanwhile.
+type pauseState struct {
+	since     time.Time
+	rejectNew bool
+}
+
+// PauseQueue stops the worker of the given type from starting queued tasks; running tasks are not affected.
+// New tasks are still queued unless rejectNew is set, in which case they fail with ErrTaskTypePaused.
+// Pausing a paused queue only updates rejectNew. The pause is audited on behalf of the actor carried by ctx.
+func (m *TaskManager) PauseQueue(ctx context.Context, taskType string, rejectNew bool) (*model.Queue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, fmt.Errorf("cannot pause queue of task type %q: %w", taskType, ErrTaskManagerClosed)
+	}
+	if _, typeExists := m.factories[taskType]; !typeExists {
+		return nil, fmt.Errorf("cannot pause queue of task type %q: %w", taskType, ErrTaskUnknownType)
+	}
+	if err := m.audit(m.queueAudit(ctx, model.AuditActionPause, taskType)); err != nil {
+		return nil, fmt.Errorf("cannot pause queue of task type %q: %w", taskType, err)
+	}
+
+	state, paused := m.paused[taskType]
+	if !paused {
+		state.since = m.clock.Now()
+	}
+	state.rejectNew = rejectNew
+	m.paused[taskType] = state
+
+	return m.queue(taskType), nil
+}
+
+// ResumeQueue lets the worker of the given type start queued tasks again.
+// Resuming a queue that is not paused has no effect. The resume is audited on behalf of the actor carried by ctx.
+func (m *TaskManager) ResumeQueue(ctx context.Context, taskType string) (*model.Queue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, typeExists := m.factories[taskType]; !typeExists {
+		return nil, fmt.Errorf("cannot resume queue of task type %q: %w", taskType, ErrTaskUnknownType)
+	}
+	if err := m.audit(m.queueAudit(ctx, model.AuditActionResume, taskType)); err != nil {
+		return nil, fmt.Errorf("cannot resume queue of task type %q: %w", taskType, err)
+	}
+
+	delete(m.paused, taskType)
+	m.notifyWorker(taskType)
+
+	return m.queue(taskType), nil
+}
+
+// GetQueue returns the state and load of the queue of the given type.
+func (m *TaskManager) GetQueue(taskType string) (*model.Queue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, typeExists := m.factories[taskType]; !typeExists {
+		return nil, fmt.Errorf("cannot find queue of task type %q: %w", taskType, ErrTaskUnknownType)
+	}
+
+	return m.queue(taskType), nil
+}
+
+// ListQueues returns the queues of all registered task types sorted by type.
+func (m *TaskManager) ListQueues() []*model.Queue {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	queues := make([]*model.Queue, 0, len(m.factories))
+	for taskType := range m.factories {
+		queues = append(queues, m.queue(taskType))
+	}
+
+	sort.Slice(queues, func(i, j int) bool { return queues[i].Type < queues[j].Type })
+
+	return queues
+}
+
+// queue describes the queue of a registered task type.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) queue(taskType string) *model.Queue {
+	now := m.clock.Now()
+	cfg := m.configs[taskType]
+	pending := m.queues[taskType]
+
+	q := &model.Queue{
+		Type:        taskType,
+		Depth:       len(pending),
+		Active:      m.active[taskType],
+		Running:     m.running[taskType],
+		QueueSize:   cfg.QueueSize,
+		Concurrency: cfg.Concurrency,
+		DeadLetters: len(m.deadOrder[taskType]),
+		Throughput:  m.through[taskType].windows(now),
+	}
+
+	if state, paused := m.paused[taskType]; paused {
+		since := state.since
+		q.Paused = true
+		q.PausedAt = &since
+		q.RejectNew = state.rejectNew
+	}
+
+	// The queue is in creation order, so its head is the oldest pending task.
+	if len(pending) > 0 {
+		q.OldestPendingMs = now.Sub(pending[0].CreatedAt).Milliseconds()
+	}
+
+	return q
+}
@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// TestPauseQueue checks that a paused queue keeps accepting tasks without starting them until resumed.
+func TestPauseQueue(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	if _, err := manager.PauseQueue(context.Background(), "mock", false); err != nil {
+		t.Fatalf("PauseQueue failed: %v", err)
+	}
+
+	tsk, err := manager.CreateTask("mock")
+	if err != nil {
+		t.Fatalf("expected a paused queue to accept tasks, got %v", err)
+	}
+	fake.Advance(time.Minute)
+
+	q, _ := manager.GetQueue("mock")
+	if !q.Paused || q.Depth != 1 || q.OldestPendingMs != time.Minute.Milliseconds() {
+		t.Fatalf("expected one task waiting for a minute in a paused queue, got %+v", q)
+	}
+	if found, _ := manager.GetTask(tsk.ID); found.Status != model.TaskStatusPending {
+		t.Fatalf("expected the task to stay pending, got %s", found.Status)
+	}
+
+	if _, err := manager.ResumeQueue(context.Background(), "mock"); err != nil {
+		t.Fatalf("ResumeQueue failed: %v", err)
+	}
+	waitUntilDone(t, manager, tsk.ID)
+
+	q, _ = manager.GetQueue("mock")
+	if q.Paused || q.Depth != 0 || q.Throughput[0].Window != "1m" || q.Throughput[0].Done != 1 {
+		t.Errorf("expected a resumed queue with one task done in the last minute, got %+v", q)
+	}
+}
+
+// TestPauseQueue_RejectNew checks that a queue paused with rejectNew rejects new tasks.
+func TestPauseQueue_RejectNew(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	if _, err := manager.PauseQueue(context.Background(), "mock", true); err != nil {
+		t.Fatalf("PauseQueue failed: %v", err)
+	}
+	if _, err := manager.CreateTask("mock"); !errors.Is(err, service.ErrTaskTypePaused) {
+		t.Errorf("expected ErrTaskTypePaused, got %v", err)
+	}
+	if _, err := manager.PauseQueue(context.Background(), "unknown", false); !errors.Is(err, service.ErrTaskUnknownType) {
+		t.Errorf("expected ErrTaskUnknownType, got %v", err)
+	}
+}
+
+// TestListQueues checks that throughput windows only count tasks finished within them.
+func TestListQueues(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+	manager.RegisterFactory("other", &mockFactory{})
+
+	tsk, _ := manager.CreateTask("mock")
+	waitUntilDone(t, manager, tsk.ID)
+	fake.Advance(2 * time.Minute)
+
+	queues := manager.ListQueues()
+	if len(queues) != 2 || queues[0].Type != "mock" || queues[1].Type != "other" {
+		t.Fatalf("expected queues sorted by type, got %+v", queues)
+	}
+
+	windows := queues[0].Throughput
+	if windows[0].Done != 0 || windows[1].Done != 1 || windows[2].Done != 1 {
+		t.Errorf("expected the task only in the 5m and 15m windows, got %+v", windows)
+	}
+}
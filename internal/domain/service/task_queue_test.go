@@ -1,72 +1,125 @@
 package service_test
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/kylerqws/task-runner/internal/domain/clock"
 	"github.com/kylerqws/task-runner/internal/domain/model"
 	"github.com/kylerqws/task-runner/internal/domain/service"
 	"github.com/kylerqws/task-runner/internal/domain/task"
 )
 
 type (
-	delayedFactory struct{} // delayedFactory creates a task that completes after a short delay.
-	delayedTask    struct{} // delayedTask sleeps for a brief period to simulate work.
+	delayedFactory struct{ clk clock.Clock } // delayedFactory creates a task that completes after a short delay.
+	delayedTask    struct{ clk clock.Clock } // delayedTask sleeps for a brief period to simulate work.
 )
 
+// taskDelay is how long a delayed task sleeps on its clock.
+const taskDelay = 200 * time.Millisecond
+
 type (
-	blockingFactory struct{}                     // blockingFactory creates a task that never completes.
-	blockingTask    struct{ hold chan struct{} } // blockingTask is a task that blocks indefinitely.
+	blockingFactory struct{} // blockingFactory creates a task that never completes.
+	blockingTask    struct { // blockingTask is a task that blocks indefinitely.
+		id   string
+		hold chan struct{}
+	}
 )
 
+// waitTimeout is how long the tests wait for a task to reach a status.
+const waitTimeout = 2 * time.Second
+
+// started holds a *startSignal per task ID, closed when a blocking or context task starts running.
+var started sync.Map
+
+// startSignal is closed once, on the first run of a task.
+type startSignal struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// startedSignal returns the start signal of the task with the given ID.
+func startedSignal(id string) *startSignal {
+	s, _ := started.LoadOrStore(id, &startSignal{ch: make(chan struct{})})
+	return s.(*startSignal)
+}
+
+// markStarted closes the start signal of the task.
+func markStarted(id string) {
+	s := startedSignal(id)
+	s.once.Do(func() { close(s.ch) })
+}
+
 // New returns a delayed task that sleeps for a short duration.
-func (*delayedFactory) New(_ *model.Task) task.ExecutableTask {
-	return &delayedTask{}
+func (f *delayedFactory) New(_ *model.Task) task.ExecutableTask {
+	return &delayedTask{clk: f.clk}
 }
 
-// Run sleeps for 200ms to simulate work.
-func (*delayedTask) Run() error {
-	time.Sleep(200 * time.Millisecond)
-	return nil
+// Run sleeps for taskDelay on the factory clock to simulate work.
+func (d *delayedTask) Run(ctx context.Context) error {
+	return clock.Sleep(ctx, d.clk, taskDelay)
 }
 
 // New returns a blocking task that never completes by default.
-func (*blockingFactory) New(_ *model.Task) task.ExecutableTask {
-	return &blockingTask{hold: make(chan struct{})}
+func (*blockingFactory) New(t *model.Task) task.ExecutableTask {
+	return &blockingTask{id: t.ID, hold: make(chan struct{})}
 }
 
 // Run blocks until the internal channel is closed.
-func (b *blockingTask) Run() error {
+func (b *blockingTask) Run(_ context.Context) error {
+	markStarted(b.id)
 	<-b.hold
 	return nil
 }
 
+// newFakeManager returns a manager driven by a fake clock.
+func newFakeManager(opts ...service.Option) (*service.TaskManager, *clock.Fake) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	return service.NewTaskManager(append([]service.Option{service.WithClock(fake)}, opts...)...), fake
+}
+
 // waitUntilDone waits for a task to complete or fails on timeout.
-func waitUntilDone(t *testing.T, manager *service.TaskManager, id string) {
+func waitUntilDone(t *testing.T, manager *service.TaskManager, id string) *model.Task {
 	t.Helper()
-	deadline := time.Now().Add(2 * time.Second)
-	for {
-		tsk, err := manager.GetTask(id)
-		if err != nil {
-			t.Fatalf("task not found: %v", err)
-		}
-		if tsk.Status == model.TaskStatusDone || tsk.Status == model.TaskStatusFailed {
-			return
-		}
-		if time.Now().After(deadline) {
-			t.Fatalf("task %s did not complete in time", id)
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+
+	tsk, err := manager.WaitTask(ctx, id)
+	if err != nil {
+		t.Fatalf("task %s did not complete in time: %v", id, err)
+	}
+	return tsk
+}
+
+// waitForStatus waits for a task to reach the given status or fails on timeout.
+// Final statuses are awaited with WaitTask; the running status only for blocking and context tasks,
+// which signal their start.
+func waitForStatus(t *testing.T, manager *service.TaskManager, id string, status model.TaskStatus) {
+	t.Helper()
+	if status.IsFinal() {
+		if tsk := waitUntilDone(t, manager, id); tsk.Status != status {
+			t.Fatalf("expected task %s to reach status %q, got %q", id, status, tsk.Status)
 		}
-		time.Sleep(10 * time.Millisecond)
+		return
+	}
+	if status != model.TaskStatusRunning {
+		t.Fatalf("cannot wait for status %q", status)
+	}
+
+	select {
+	case <-startedSignal(id).ch:
+	case <-time.After(waitTimeout):
+		t.Fatalf("task %s did not start in time", id)
 	}
 }
 
 // TestSequentialExecution_PerTaskType ensures only one task runs at a time per type.
 func TestSequentialExecution_PerTaskType(t *testing.T) {
-	manager := service.NewTaskManager()
-	manager.RegisterFactory("delayed", &delayedFactory{})
-
-	start := time.Now()
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("delayed", &delayedFactory{clk: fake})
 
 	t1, err := manager.CreateTask("delayed")
 	if err != nil {
@@ -77,12 +130,17 @@ func TestSequentialExecution_PerTaskType(t *testing.T) {
 		t.Fatalf("unexpected error creating second task: %v", err)
 	}
 
+	fake.BlockUntil(1)
+	fake.Advance(taskDelay)
 	waitUntilDone(t, manager, t1.ID)
-	waitUntilDone(t, manager, t2.ID)
 
-	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
-		t.Errorf("expected sequential execution, but took only %v", elapsed)
+	if got, _ := manager.GetTask(t2.ID); got.Status.IsFinal() {
+		t.Errorf("expected sequential execution, but second task is already %q", got.Status)
 	}
+
+	fake.BlockUntil(1)
+	fake.Advance(taskDelay)
+	waitUntilDone(t, manager, t2.ID)
 }
 
 // TestCreateTask_QueueOverflow ensures queue size is enforced.
@@ -107,3 +165,186 @@ func TestCreateTask_QueueOverflow(t *testing.T) {
 		t.Error("expected nil task on overflow")
 	}
 }
+
+type (
+	ctxFactory struct{}            // ctxFactory creates a task that runs until its context is done.
+	ctxTask    struct{ id string } // ctxTask blocks until the context is cancelled.
+)
+
+// New returns a task that waits for its context.
+func (*ctxFactory) New(t *model.Task) task.ExecutableTask {
+	return &ctxTask{id: t.ID}
+}
+
+// Run blocks until the context is done and returns its error.
+func (c *ctxTask) Run(ctx context.Context) error {
+	markStarted(c.id)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// TestConcurrentExecution_PerTaskType ensures tasks of one type run in parallel up to the configured limit.
+func TestConcurrentExecution_PerTaskType(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactoryWithConfig("delayed", &delayedFactory{clk: fake}, service.TypeConfig{QueueSize: 10, Concurrency: 2})
+
+	t1, err := manager.CreateTask("delayed")
+	if err != nil {
+		t.Fatalf("unexpected error creating first task: %v", err)
+	}
+	t2, err := manager.CreateTask("delayed")
+	if err != nil {
+		t.Fatalf("unexpected error creating second task: %v", err)
+	}
+
+	fake.BlockUntil(2)
+	fake.Advance(taskDelay)
+
+	waitUntilDone(t, manager, t1.ID)
+	waitUntilDone(t, manager, t2.ID)
+}
+
+// TestCreateTask_ConfiguredQueueSize ensures the per-type queue size is enforced.
+func TestCreateTask_ConfiguredQueueSize(t *testing.T) {
+	manager := service.NewTaskManager(service.WithDefaultTypeConfig(service.TypeConfig{QueueSize: 2}))
+	manager.RegisterFactory("blocked", &blockingFactory{})
+
+	for i := 0; i < 2; i++ {
+		if _, err := manager.CreateTask("blocked"); err != nil {
+			t.Fatalf("unexpected error while filling queue: %v", err)
+		}
+	}
+
+	if _, err := manager.CreateTask("blocked"); !errors.Is(err, service.ErrTaskQueueLimitReached) {
+		t.Errorf("expected ErrTaskQueueLimitReached, got: %v", err)
+	}
+}
+
+// TestRunTask_Timeout ensures a task exceeding its timeout is marked as failed.
+func TestRunTask_Timeout(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactoryWithConfig("ctx", &ctxFactory{}, service.TypeConfig{Timeout: time.Minute})
+
+	tsk, err := manager.CreateTask("ctx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+	waitUntilDone(t, manager, tsk.ID)
+
+	got, _ := manager.GetTask(tsk.ID)
+	if got.Status != model.TaskStatusFailed {
+		t.Errorf("expected status 'failed', got %q", got.Status)
+	}
+}
+
+// TestUpdateTypeConfig_QueueSize ensures a reduced queue size applies to new tasks
+// without dropping queued ones.
+func TestUpdateTypeConfig_QueueSize(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("blocked", &blockingFactory{})
+
+	for i := 0; i < 3; i++ {
+		if _, err := manager.CreateTask("blocked"); err != nil {
+			t.Fatalf("unexpected error while filling queue: %v", err)
+		}
+	}
+
+	if err := manager.UpdateTypeConfig("blocked", service.TypeConfig{QueueSize: 2, Concurrency: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.CreateTask("blocked"); !errors.Is(err, service.ErrTaskQueueLimitReached) {
+		t.Errorf("expected ErrTaskQueueLimitReached, got: %v", err)
+	}
+}
+
+// TestUnregisterFactory_DrainsQueue ensures queued tasks still run after unregistering
+// and the type can be registered again afterwards.
+func TestUnregisterFactory_DrainsQueue(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("delayed", &delayedFactory{clk: fake})
+
+	tsk, err := manager.CreateTask("delayed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.UnregisterFactory("delayed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fake.BlockUntil(1)
+	fake.Advance(taskDelay)
+	waitUntilDone(t, manager, tsk.ID)
+
+	manager.RegisterFactory("delayed", &delayedFactory{clk: fake})
+	if _, err := manager.CreateTask("delayed"); err != nil {
+		t.Errorf("expected type to be registered again, got: %v", err)
+	}
+}
+
+// TestCancelTask_Running ensures a running task has its context cancelled and ends as canceled.
+func TestCancelTask_Running(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.RegisterFactory("ctx", &ctxFactory{})
+
+	tsk, err := manager.CreateTask("ctx")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	waitForStatus(t, manager, tsk.ID, model.TaskStatusRunning)
+
+	if err := manager.CancelTask(context.Background(), tsk.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	waitUntilDone(t, manager, tsk.ID)
+
+	got, _ := manager.GetTask(tsk.ID)
+	if got.Status != model.TaskStatusCanceled {
+		t.Errorf("expected status 'canceled', got %q", got.Status)
+	}
+}
+
+// TestTaskTimings ensures timestamps are recorded and durations are derived from them on read.
+func TestTaskTimings(t *testing.T) {
+	manager, fake := newFakeManager()
+	manager.RegisterFactory("delayed", &delayedFactory{clk: fake})
+	created := fake.Now()
+
+	t1, _ := manager.CreateTask("delayed")
+	t2, _ := manager.CreateTask("delayed")
+
+	fake.BlockUntil(1)
+	fake.Advance(taskDelay)
+	waitUntilDone(t, manager, t1.ID)
+
+	fake.BlockUntil(1) // The second task has started and sleeps on the clock
+	fake.Advance(50 * time.Millisecond)
+
+	running, _ := manager.GetTask(t2.ID)
+	if running.FinishedAt != nil || running.QueuedDurationMs != 200 || running.RunDurationMs != 50 {
+		t.Errorf("unexpected timings of running task: %+v", running)
+	}
+
+	fake.Advance(taskDelay - 50*time.Millisecond)
+	waitUntilDone(t, manager, t2.ID)
+
+	first, _ := manager.GetTask(t1.ID)
+	if first.QueuedDurationMs != 0 || first.RunDurationMs != 200 || first.Duration != "200ms" {
+		t.Errorf("unexpected timings of first task: %+v", first)
+	}
+
+	second, _ := manager.GetTask(t2.ID)
+	if second.StartedAt == nil || !second.StartedAt.Equal(created.Add(taskDelay)) {
+		t.Errorf("expected started_at %v, got %v", created.Add(taskDelay), second.StartedAt)
+	}
+	if second.FinishedAt == nil || !second.FinishedAt.Equal(created.Add(2*taskDelay)) {
+		t.Errorf("expected finished_at %v, got %v", created.Add(2*taskDelay), second.FinishedAt)
+	}
+	if second.QueuedDurationMs != 200 || second.RunDurationMs != 200 {
+		t.Errorf("unexpected timings of second task: %+v", second)
+	}
+}
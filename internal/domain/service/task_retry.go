@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/logging"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// RetryTask queues a finished task again and returns a snapshot of the queued task.
+// By default the type, parameters, and owner are cloned into a new task linked by RetryOf.
+// With inPlace set, the task keeps its ID: its last run is appended to Attempts and it is queued
+// again with a fresh creation time, leaving the dead letters if it was there.
+// The queued task records the request ID and trace carried by ctx, and the retry is audited on behalf of its actor.
+// The limits of the owner of the task apply; exceeding them returns a *ClientLimitError.
+// Pending and running tasks are refused with ErrTaskNotFinished.
+func (m *TaskManager) RetryTask(ctx context.Context, id string, inPlace bool) (*model.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, taskExists := m.findTask(id)
+	if !taskExists {
+		return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, ErrTaskNotFound)
+	}
+	if !t.Status.IsFinal() {
+		return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, ErrTaskNotFinished)
+	}
+	if err := m.checkCreate(t.Type, t.Params, 0); err != nil {
+		return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, err)
+	}
+	if err := m.checkClient(t.Owner, 1); err != nil {
+		return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, err)
+	}
+
+	now := m.clock.Now()
+
+	if !inPlace {
+		retry, err := m.newTask(ctx, t.Owner, t.Type, t.Params, "")
+		if err != nil {
+			return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, err)
+		}
+		retry.RetryOf = id
+		if err := m.audit(m.taskAudit(ctx, model.AuditActionRetry, retry, "", retry.Status)); err != nil {
+			return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, err)
+		}
+
+		m.chargeClient(t.Owner, 1)
+		m.addTask(retry)
+
+		return retry.Snapshot(now), nil
+	}
+
+	if err := m.audit(m.taskAudit(ctx, model.AuditActionRetry, t, t.Status, model.TaskStatusPending)); err != nil {
+		return nil, fmt.Errorf("cannot retry task with ID %q: %w", id, err)
+	}
+
+	m.chargeClient(t.Owner, 1)
+	m.requeueTask(ctx, t, now)
+
+	return t.Snapshot(now), nil
+}
+
+// requeueTask records the last run of a finished task in its attempts and queues it again
+// on behalf of the request carried by ctx, whose ID and trace replace the previous ones if set.
+// A task taken from the dead letters is moved back to the task list.
+// WARNING: Must be called with m.mu.Lock held, after checkCreate.
+func (m *TaskManager) requeueTask(ctx context.Context, t *model.Task, now time.Time) {
+	m.removeDeadLetter(t.ID)
+
+	t.Attempts = append(t.Attempts, model.TaskAttempt{
+		QueuedAt:   t.CreatedAt,
+		StartedAt:  t.StartedAt,
+		FinishedAt: t.FinishedAt,
+		Status:     t.Status,
+		Result:     t.Result,
+	})
+
+	t.Status = model.TaskStatusPending
+	t.CreatedAt = now
+	t.StartedAt = nil
+	t.FinishedAt = nil
+	t.Result = ""
+	t.Output = nil
+	if id := logging.RequestID(ctx); id != "" {
+		t.RequestID = id
+	}
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		t.TraceParent = sc.Traceparent()
+	}
+
+	m.tasks[t.ID] = t
+	m.finished[t.ID] = make(chan struct{})
+	m.enqueueTask(t)
+
+	m.logTask(slog.LevelInfo, logTaskRequeued, t)
+}
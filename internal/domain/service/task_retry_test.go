@@ -0,0 +1,70 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+)
+
+// TestRetryTask_Clone checks that a retry creates a new task with the same type and params linked to the original.
+func TestRetryTask_Clone(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	original, _ := manager.CreateTaskWithParams("mock", []byte(`{"n":1}`))
+	waitUntilDone(t, manager, original.ID)
+
+	retry, err := manager.RetryTask(context.Background(), original.ID, false)
+	if err != nil {
+		t.Fatalf("RetryTask failed: %v", err)
+	}
+	if retry.ID == original.ID || retry.RetryOf != original.ID || string(retry.Params) != `{"n":1}` {
+		t.Fatalf("expected a new task linked to the original, got %+v", retry)
+	}
+	waitUntilDone(t, manager, retry.ID)
+}
+
+// TestRetryTask_InPlace checks that an in-place retry keeps the ID and records the previous run.
+func TestRetryTask_InPlace(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	tsk, _ := manager.CreateTask("mock")
+	waitUntilDone(t, manager, tsk.ID)
+
+	retry, err := manager.RetryTask(context.Background(), tsk.ID, true)
+	if err != nil {
+		t.Fatalf("RetryTask failed: %v", err)
+	}
+	if retry.ID != tsk.ID || len(retry.Attempts) != 1 || retry.Attempts[0].Status != model.TaskStatusDone {
+		t.Fatalf("expected the same task with one recorded attempt, got %+v", retry)
+	}
+
+	waitUntilDone(t, manager, tsk.ID)
+	found, _ := manager.GetTask(tsk.ID)
+	if found.Status != model.TaskStatusDone || found.StartedAt == nil || len(found.Attempts) != 1 {
+		t.Errorf("expected the task to run again, got %+v", found)
+	}
+}
+
+// TestRetryTask_Unfinished checks that pending and running tasks cannot be retried.
+func TestRetryTask_Unfinished(t *testing.T) {
+	manager, _ := newFakeManager()
+	manager.RegisterFactory("blocked", &blockingFactory{})
+
+	running, _ := manager.CreateTask("blocked")
+	pending, _ := manager.CreateTask("blocked")
+	waitForStatus(t, manager, running.ID, model.TaskStatusRunning)
+
+	for _, id := range []string{running.ID, pending.ID} {
+		if _, err := manager.RetryTask(context.Background(), id, false); !errors.Is(err, service.ErrTaskNotFinished) {
+			t.Errorf("expected ErrTaskNotFinished, got %v", err)
+		}
+	}
+	if _, err := manager.RetryTask(context.Background(), "missing", true); !errors.Is(err, service.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+const (
+	throughputBucket  = 10 * time.Second // Resolution of the throughput windows
+	throughputBuckets = 90               // Buckets kept, covering the longest window
+)
+
+// throughputWindows are the windows reported by GetQueue, each a multiple of throughputBucket.
+var throughputWindows = []struct {
+	name   string
+	length time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+}
+
+// throughputSlot counts the tasks finished within one bucket.
+type throughputSlot struct {
+	start    int64 // Bucket index since the Unix epoch
+	done     int
+	failed   int
+	canceled int
+}
+
+// throughput counts the tasks finished by the workers of a type in a ring of fixed-size buckets.
+type throughput struct {
+	slots [throughputBuckets]throughputSlot
+}
+
+// record counts a task finished at the given time with the given status.
+func (tp *throughput) record(now time.Time, status model.TaskStatus) {
+	index := now.UnixNano() / int64(throughputBucket)
+	slot := &tp.slots[index%throughputBuckets]
+	if slot.start != index {
+		*slot = throughputSlot{start: index}
+	}
+
+	switch status {
+	case model.TaskStatusDone:
+		slot.done++
+	case model.TaskStatusFailed:
+		slot.failed++
+	case model.TaskStatusCanceled:
+		slot.canceled++
+	}
+}
+
+// windows returns the counters of every throughput window ending at the given time.
+func (tp *throughput) windows(now time.Time) []model.QueueThroughput {
+	index := now.UnixNano() / int64(throughputBucket)
+
+	result := make([]model.QueueThroughput, 0, len(throughputWindows))
+	for _, w := range throughputWindows {
+		first := index - int64(w.length/throughputBucket) + 1
+		counts := model.QueueThroughput{Window: w.name}
+
+		for _, slot := range tp.slots {
+			if slot.start >= first && slot.start <= index {
+				counts.Done += slot.done
+				counts.Failed += slot.failed
+				counts.Canceled += slot.canceled
+			}
+		}
+
+		result = append(result, counts)
+	}
+
+	return result
+}
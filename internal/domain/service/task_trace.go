@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// Names of the spans recorded for every task run.
+const (
+	spanTaskQueue = "task.queue"
+	spanTaskRun   = "task.run"
+)
+
+// startSpans records the time a task waited in the queue as a span and starts the span of its run,
+// both continuing the trace of the request that queued the task (see model.Task.TraceParent).
+// Without one, the queue span starts a new trace. The run span is linked to the queue span
+// and carried by the returned context, so that tasks can continue the trace in their calls.
+// WARNING: Must be called with m.mu held.
+func (m *TaskManager) startSpans(ctx context.Context, t *model.Task, now time.Time) context.Context {
+	if m.tracer == nil {
+		return ctx
+	}
+
+	attrs := []slog.Attr{
+		slog.String("task.id", t.ID),
+		slog.String("task.type", t.Type),
+		slog.Int("task.attempt", len(t.Attempts)+1),
+	}
+	if t.Owner != "" {
+		attrs = append(attrs, slog.String("task.owner", t.Owner))
+	}
+
+	parent, _ := tracing.ParseTraceparent(t.TraceParent)
+
+	_, wait := m.tracer.Start(ctx, spanTaskQueue,
+		tracing.WithParent(parent),
+		tracing.WithStartTime(t.CreatedAt),
+		tracing.WithAttributes(attrs...),
+	)
+	wait.EndAt(now)
+
+	if !parent.IsValid() {
+		parent = wait.SpanContext()
+	}
+
+	ctx, _ = m.tracer.Start(ctx, spanTaskRun,
+		tracing.WithKind(tracing.SpanKindConsumer),
+		tracing.WithParent(parent),
+		tracing.WithStartTime(now),
+		tracing.WithLinks(wait.SpanContext()),
+		tracing.WithAttributes(attrs...),
+	)
+
+	return ctx
+}
+
+// endRunSpan ends the run span of a finished task with its final status.
+func endRunSpan(span *tracing.Span, t *model.Task, now time.Time) {
+	span.SetAttributes(slog.String("task.status", string(t.Status)))
+	if t.Status == model.TaskStatusFailed {
+		span.SetStatus(tracing.StatusError, t.Result)
+	}
+	span.EndAt(now)
+}
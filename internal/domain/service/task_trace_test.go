@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// TestTracer checks that the queue wait and run of a task continue the trace of the request that queued it.
+func TestTracer(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := tracing.NewTracer(exporter)
+	manager, fake := newFakeManager(service.WithTracer(tracer))
+	manager.RegisterFactory("fail", &failingFactory{})
+	if _, err := manager.PauseQueue(context.Background(), "fail", false); err != nil {
+		t.Fatalf("PauseQueue failed: %v", err)
+	}
+
+	ctx, request := tracer.Start(context.Background(), "HTTP POST")
+	tsk, err := manager.CreateTaskFor(ctx, "", "fail", nil)
+	if err != nil {
+		t.Fatalf("CreateTaskFor failed: %v", err)
+	}
+	request.End()
+	if tsk.TraceParent != request.SpanContext().Traceparent() {
+		t.Errorf("expected traceparent %q, got %q", request.SpanContext().Traceparent(), tsk.TraceParent)
+	}
+
+	fake.Advance(time.Minute)
+	if _, err := manager.ResumeQueue(context.Background(), "fail"); err != nil {
+		t.Fatalf("ResumeQueue failed: %v", err)
+	}
+	waitUntilDone(t, manager, tsk.ID)
+
+	spans := make(map[string]tracing.SpanData)
+	for _, s := range exporter.Spans() {
+		spans[s.Name] = s
+	}
+	wait, run := spans["task.queue"], spans["task.run"]
+
+	for _, s := range []tracing.SpanData{wait, run} {
+		if s.SpanContext.TraceID != request.SpanContext().TraceID || s.Parent.SpanID != request.SpanContext().SpanID {
+			t.Errorf("expected span %q to be a child of the request span, got %+v", s.Name, s)
+		}
+	}
+	if wait.End.Sub(wait.Start) != time.Minute {
+		t.Errorf("expected the queue span to last the queue wait, got %s", wait.End.Sub(wait.Start))
+	}
+	if run.Status != tracing.StatusError || len(run.Links) != 1 || run.Links[0] != wait.SpanContext {
+		t.Errorf("expected a failed run span linked to the queue span, got %+v", run)
+	}
+}
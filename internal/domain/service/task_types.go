@@ -0,0 +1,40 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// ListTaskTypes returns all registered task types sorted by name,
+// with their current limits and live statistics.
+func (m *TaskManager) ListTaskTypes() []*model.TaskType {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	types := make([]*model.TaskType, 0, len(m.factories))
+	for taskType, desc := range m.factories {
+		cfg := m.configs[taskType]
+
+		types = append(types, &model.TaskType{
+			Type:         taskType,
+			Description:  desc.Description,
+			ParamsSchema: desc.ParamsSchema,
+			Disabled:     m.draining[taskType],
+			QueueSize:    cfg.QueueSize,
+			Concurrency:  cfg.Concurrency,
+			TimeoutMs:    cfg.Timeout.Milliseconds(),
+			Stats: model.TaskTypeStats{
+				Pending:  len(m.queues[taskType]),
+				Running:  m.running[taskType],
+				Done:     m.done[taskType],
+				Failed:   m.failed[taskType],
+				Canceled: m.canceled[taskType],
+			},
+		})
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i].Type < types[j].Type })
+
+	return types
+}
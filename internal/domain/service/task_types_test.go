@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+)
+
+// TestListTaskTypes checks that registered types are listed with limits and live stats.
+func TestListTaskTypes(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.Register(task.Descriptor{
+		Type:         "blocked",
+		Description:  "blocks forever",
+		ParamsSchema: json.RawMessage(`{"type":"object"}`),
+		Factory:      &blockingFactory{},
+	}, service.TypeConfig{QueueSize: 5, Concurrency: 1, Timeout: time.Minute})
+	manager.RegisterFactory("mock", &mockFactory{})
+
+	first, err := manager.CreateTask("blocked")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := manager.CreateTask("blocked"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	waitForStatus(t, manager, first.ID, model.TaskStatusRunning)
+
+	types := manager.ListTaskTypes()
+	if len(types) != 2 || types[0].Type != "blocked" || types[1].Type != "mock" {
+		t.Fatalf("expected types [blocked mock], got %+v", types)
+	}
+
+	blocked := types[0]
+	if blocked.Description != "blocks forever" || string(blocked.ParamsSchema) != `{"type":"object"}` {
+		t.Errorf("unexpected descriptor fields: %+v", blocked)
+	}
+	if blocked.QueueSize != 5 || blocked.Concurrency != 1 || blocked.TimeoutMs != 60000 {
+		t.Errorf("unexpected limits: %+v", blocked)
+	}
+	if blocked.Stats.Pending != 2 || blocked.Stats.Running != 1 {
+		t.Errorf("expected 2 pending and 1 running, got %+v", blocked.Stats)
+	}
+}
@@ -1,30 +1,49 @@
 package task
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"time"
 
+	"github.com/kylerqws/task-runner/internal/domain/clock"
 	"github.com/kylerqws/task-runner/internal/domain/model"
 )
 
-// DefaultTask simulates a task with a fixed delay and random failure chance.
+// Forced outcomes of a DefaultTask.
+const (
+	DefaultOutcomeDone   = "done"   // The task always succeeds
+	DefaultOutcomeFailed = "failed" // The task always fails
+)
+
+// DefaultParams holds the optional per-task overrides of a "default" task.
+type DefaultParams struct {
+	Delay              string   `json:"delay,omitempty"`               // Fixed delay (e.g. "2s") instead of a random one
+	FailureProbability *float64 `json:"failure_probability,omitempty"` // Probability of failure (0..1)
+	Seed               *int64   `json:"seed,omitempty"`                // Seed making the delay and outcome deterministic
+	Outcome            string   `json:"outcome,omitempty"`             // Forced outcome ("done" or "failed")
+}
+
+// DefaultTask simulates a task with a delay and a predetermined outcome.
 type DefaultTask struct {
 	meta  *model.Task
-	rng   *rand.Rand
+	clock clock.Clock
 	delay time.Duration
+	fail  bool
 }
 
-// NewDefaultTask creates a new DefaultTask with the given metadata, RNG, and delay.
-func NewDefaultTask(meta *model.Task, rng *rand.Rand, delay time.Duration) *DefaultTask {
-	return &DefaultTask{meta: meta, rng: rng, delay: delay}
+// NewDefaultTask creates a new DefaultTask with the given metadata, clock, delay, and outcome.
+func NewDefaultTask(meta *model.Task, clk clock.Clock, delay time.Duration, fail bool) *DefaultTask {
+	return &DefaultTask{meta: meta, clock: clk, delay: delay, fail: fail}
 }
 
-// Run simulates task execution by sleeping for a predefined delay.
-// It randomly returns an error to mimic failure in ~40% of cases.
-func (t *DefaultTask) Run() error {
-	time.Sleep(t.delay)
-	if t.rng.Intn(100) >= 60 {
+// Run simulates task execution by sleeping for the task delay.
+// It returns an error if the task was chosen to fail.
+func (t *DefaultTask) Run(ctx context.Context) error {
+	if err := clock.Sleep(ctx, t.clock, t.delay); err != nil {
+		return err
+	}
+
+	if t.fail {
 		return fmt.Errorf("simulated task failure")
 	}
 
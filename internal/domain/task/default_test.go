@@ -0,0 +1,132 @@
+package task_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/clock"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+)
+
+// runDefaultTask creates a default task with the given parameters, runs it, and returns its duration and error.
+func runDefaultTask(t *testing.T, factory *task.DefaultTaskFactory, params string) (time.Duration, error) {
+	t.Helper()
+	if err := factory.ValidateParams(json.RawMessage(params)); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	meta := model.NewTask("test", task.DefaultTaskType, time.Now())
+	meta.Params = json.RawMessage(params)
+
+	start := time.Now()
+	err := factory.New(meta).Run(context.Background())
+
+	return time.Since(start), err
+}
+
+// TestDefaultTask_ForcedOutcome checks that the outcome override wins over the failure rate.
+func TestDefaultTask_ForcedOutcome(t *testing.T) {
+	factory := &task.DefaultTaskFactory{FailureRate: 1}
+
+	if _, err := runDefaultTask(t, factory, `{"outcome":"done"}`); err != nil {
+		t.Errorf("expected forced success, got %v", err)
+	}
+	if _, err := runDefaultTask(t, factory, `{"outcome":"failed","failure_probability":0}`); err == nil {
+		t.Error("expected forced failure")
+	}
+}
+
+// TestDefaultTask_FailureProbability checks the per-task failure probability override.
+func TestDefaultTask_FailureProbability(t *testing.T) {
+	factory := &task.DefaultTaskFactory{FailureRate: 0}
+
+	if _, err := runDefaultTask(t, factory, `{"failure_probability":1}`); err == nil {
+		t.Error("expected failure with probability 1")
+	}
+	if _, err := runDefaultTask(t, factory, `{}`); err != nil {
+		t.Errorf("expected success with failure rate 0, got %v", err)
+	}
+}
+
+// TestDefaultTask_Delay checks that the fixed delay override is honoured on the factory clock.
+func TestDefaultTask_Delay(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	factory := &task.DefaultTaskFactory{MinDelay: time.Hour, MaxDelay: time.Hour, Clock: fake}
+
+	params := `{"delay":"20s","outcome":"done"}`
+	if err := factory.ValidateParams(json.RawMessage(params)); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	meta := model.NewTask("test", task.DefaultTaskType, fake.Now())
+	meta.Params = json.RawMessage(params)
+
+	done := make(chan error, 1)
+	go func() { done <- factory.New(meta).Run(context.Background()) }()
+
+	fake.BlockUntil(1)
+	fake.Advance(19 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("expected the task to wait for the full delay")
+	default:
+	}
+
+	fake.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+}
+
+// TestDefaultTask_Seed checks that tasks with the same seed get the same outcome.
+func TestDefaultTask_Seed(t *testing.T) {
+	factory := &task.DefaultTaskFactory{MaxDelay: time.Millisecond, FailureRate: 0.5}
+
+	for seed := 0; seed < 10; seed++ {
+		params := fmt.Sprintf(`{"seed":%d}`, seed)
+		_, first := runDefaultTask(t, factory, params)
+		_, second := runDefaultTask(t, factory, params)
+
+		if (first == nil) != (second == nil) {
+			t.Errorf("seed %d: expected the same outcome, got %v and %v", seed, first, second)
+		}
+	}
+}
+
+// TestDefaultTaskFactory_Concurrent checks that the shared generator is safe for concurrent use.
+func TestDefaultTaskFactory_Concurrent(t *testing.T) {
+	factory := &task.DefaultTaskFactory{MaxDelay: time.Second, FailureRate: 0.5}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			factory.New(model.NewTask("test", task.DefaultTaskType, time.Now()))
+		}()
+	}
+	wg.Wait()
+}
+
+// TestDefaultTaskFactory_ValidateParams checks that invalid overrides are rejected.
+func TestDefaultTaskFactory_ValidateParams(t *testing.T) {
+	factory := &task.DefaultTaskFactory{}
+
+	invalid := []string{
+		`{"delay":"soon"}`,
+		`{"delay":"-1s"}`,
+		`{"failure_probability":1.5}`,
+		`{"outcome":"maybe"}`,
+		`{"unknown":true}`,
+	}
+	for _, params := range invalid {
+		if err := factory.ValidateParams(json.RawMessage(params)); err == nil {
+			t.Errorf("expected %s to be rejected", params)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package task
+
+import "encoding/json"
+
+// Descriptor describes a task type for registration and discovery.
+type Descriptor struct {
+	Type         string          // Task type identifier
+	Description  string          // Human-readable description
+	ParamsSchema json.RawMessage // JSON schema of the task parameters (optional)
+	Factory      Factory         // Factory creating tasks of this type
+}
@@ -0,0 +1,289 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+const (
+	// execDefaultMaxOutput is the default limit of captured bytes per output stream.
+	execDefaultMaxOutput = 64 * 1024
+
+	// execWaitDelay bounds how long Run waits for output pipes after the process is killed.
+	execWaitDelay = 5 * time.Second
+)
+
+// ExecParams holds the parameters of an "exec" task taken from the task payload.
+type ExecParams struct {
+	Command string   `json:"command"`        // Allow-listed command name
+	Args    []string `json:"args,omitempty"` // Command arguments
+}
+
+// ExecOutput holds the structured result of an "exec" task.
+type ExecOutput struct {
+	ExitCode        int    `json:"exit_code"`                  // Process exit code (-1 if it did not exit normally)
+	Stdout          string `json:"stdout"`                     // Captured standard output
+	Stderr          string `json:"stderr"`                     // Captured standard error
+	StdoutTruncated bool   `json:"stdout_truncated,omitempty"` // Standard output exceeded the size limit
+	StderrTruncated bool   `json:"stderr_truncated,omitempty"` // Standard error exceeded the size limit
+}
+
+// ExecTask runs an allow-listed command and captures its output.
+type ExecTask struct {
+	factory *ExecTaskFactory
+	params  ExecParams
+	output  ExecOutput
+}
+
+// Run starts the command in its own process group and waits for it to exit.
+// The whole process group is killed when the context is done.
+// A non-zero exit code is reported as an error.
+func (t *ExecTask) Run(ctx context.Context) error {
+	path, err := t.factory.commandPath(t.params.Command)
+	if err != nil {
+		return err
+	}
+
+	stdout := newCappedBuffer(t.factory.maxOutput())
+	stderr := newCappedBuffer(t.factory.maxOutput())
+
+	cmd := exec.CommandContext(ctx, path, t.params.Args...)
+	cmd.Dir = t.factory.Dir
+	cmd.Env = append([]string{}, t.factory.Env...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = execWaitDelay
+	setProcessGroup(cmd)
+
+	err = cmd.Run()
+
+	t.output = ExecOutput{
+		ExitCode:        cmd.ProcessState.ExitCode(),
+		Stdout:          stdout.String(),
+		Stderr:          stderr.String(),
+		StdoutTruncated: stdout.Truncated(),
+		StderrTruncated: stderr.Truncated(),
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		return fmt.Errorf("command %q exited with code %d", t.params.Command, exitErr.ExitCode())
+	}
+
+	return err
+}
+
+// Output returns the exit code and captured output of the command.
+func (t *ExecTask) Output() any {
+	return t.output
+}
+
+// ExecTaskFactory creates instances of ExecTask restricted to the configured commands.
+// The working directory and environment are set by the server and never taken from the payload.
+// Commands are looked up in the PATH of Env, never in the PATH of the server.
+type ExecTaskFactory struct {
+	AllowedCommands []string // Command names accepted in the payload
+	Dir             string   // Working directory of the process
+	Env             []string // Complete environment of the process ("KEY=value"), nothing is inherited
+	MaxOutputBytes  int      // Max captured bytes per output stream
+
+	paths map[string]string // Absolute path of each allowed command (set by Resolve)
+}
+
+// Resolve looks up the absolute path of every allowed command once, so that tasks run the binaries
+// found when the factory is built. Commands of a factory that is not resolved are looked up on every run.
+func (f *ExecTaskFactory) Resolve() error {
+	paths := make(map[string]string, len(f.AllowedCommands))
+	for _, name := range f.AllowedCommands {
+		path, err := f.lookPath(name)
+		if err != nil {
+			return err
+		}
+		paths[name] = path
+	}
+
+	f.paths = paths
+
+	return nil
+}
+
+// New creates a new ExecTask from the task parameters.
+// The parameters are expected to be validated by ValidateParams beforehand.
+func (f *ExecTaskFactory) New(task *model.Task) ExecutableTask {
+	t := &ExecTask{factory: f}
+	_ = json.Unmarshal(task.Params, &t.params)
+
+	return t
+}
+
+// ValidateParams checks that the payload names an allow-listed command.
+func (f *ExecTaskFactory) ValidateParams(params json.RawMessage) error {
+	var p ExecParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.Command == "" {
+		return errors.New("command is required")
+	}
+	if !slices.Contains(f.AllowedCommands, p.Command) {
+		return fmt.Errorf("command %q is not allowed", p.Command)
+	}
+
+	return nil
+}
+
+// commandPath returns the absolute path of an allowed command.
+func (f *ExecTaskFactory) commandPath(name string) (string, error) {
+	if !slices.Contains(f.AllowedCommands, name) {
+		return "", fmt.Errorf("command %q is not allowed", name)
+	}
+	if path, ok := f.paths[name]; ok {
+		return path, nil
+	}
+
+	return f.lookPath(name)
+}
+
+// lookPath resolves a command like exec.LookPath, but in the PATH of Env and relative to Dir.
+// Names containing a separator are taken as paths and are not looked up in the PATH.
+func (f *ExecTaskFactory) lookPath(name string) (string, error) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.ContainsRune(name, '/') {
+		path := f.absPath(name)
+		if err := checkExecutable(path); err != nil {
+			return "", fmt.Errorf("cannot resolve command %q: %w", name, err)
+		}
+		return path, nil
+	}
+
+	for _, dir := range filepath.SplitList(f.envPath()) {
+		if dir == "" {
+			continue // The empty entry (current directory) is ignored, like exec.LookPath does
+		}
+		path := f.absPath(filepath.Join(dir, name))
+		if checkExecutable(path) == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("cannot resolve command %q: not found in PATH %q of the configured environment", name, f.envPath())
+}
+
+// absPath returns the path made absolute relative to Dir, or to the working directory of the server if Dir is empty.
+func (f *ExecTaskFactory) absPath(path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(f.Dir, path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return path
+}
+
+// envPath returns the PATH variable of Env (empty if not set).
+func (f *ExecTaskFactory) envPath() string {
+	var path string
+	for _, kv := range f.Env {
+		if v, ok := strings.CutPrefix(kv, "PATH="); ok {
+			path = v // The last definition wins, like in the environment of the process
+		}
+	}
+
+	return path
+}
+
+// checkExecutable returns an error unless path is a regular file that can be executed.
+func checkExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() || !isExecutable(info) {
+		return fmt.Errorf("%s: %w", path, fs.ErrPermission)
+	}
+
+	return nil
+}
+
+// maxOutput returns the configured output limit or the default one.
+func (f *ExecTaskFactory) maxOutput() int {
+	if f.MaxOutputBytes > 0 {
+		return f.MaxOutputBytes
+	}
+
+	return execDefaultMaxOutput
+}
+
+// decodeParams strictly decodes the task parameters into dst.
+func decodeParams(params json.RawMessage, dst any) error {
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("cannot decode params: %w", err)
+	}
+
+	return nil
+}
+
+// cappedBuffer is a concurrency-safe writer that keeps at most limit bytes
+// and silently discards the rest.
+type cappedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// newCappedBuffer creates a buffer keeping at most limit bytes.
+func newCappedBuffer(limit int) *cappedBuffer {
+	return &cappedBuffer{limit: limit}
+}
+
+// Write stores as much of p as fits and always reports the full length as written.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// String returns the captured bytes.
+func (b *cappedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// Truncated reports whether some bytes were discarded.
+func (b *cappedBuffer) Truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.truncated
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package task
+
+import (
+	"io/fs"
+	"os/exec"
+)
+
+// isExecutable reports whether the file is regular, since there are no execute permission bits to check.
+func isExecutable(info fs.FileInfo) bool {
+	return info.Mode().IsRegular()
+}
+
+// setProcessGroup is a no-op on platforms without process groups;
+// cancellation kills only the started process.
+func setProcessGroup(_ *exec.Cmd) {}
@@ -0,0 +1,155 @@
+//go:build unix
+
+package task_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+)
+
+// testEnv is the environment of the test commands, which are not looked up in the PATH of the test process.
+var testEnv = []string{"PATH=/usr/bin:/bin"}
+
+// newExecTask builds an exec task from the given parameters using a permissive test factory.
+func newExecTask(t *testing.T, factory *task.ExecTaskFactory, params string) *task.ExecTask {
+	t.Helper()
+	if err := factory.ValidateParams(json.RawMessage(params)); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	meta := model.NewTask("test", task.ExecTaskType, time.Now())
+	meta.Params = json.RawMessage(params)
+
+	return factory.New(meta).(*task.ExecTask)
+}
+
+// TestExecTask_Success checks that stdout and the exit code are captured.
+func TestExecTask_Success(t *testing.T) {
+	factory := &task.ExecTaskFactory{AllowedCommands: []string{"echo"}, Env: testEnv}
+	exec := newExecTask(t, factory, `{"command":"echo","args":["hello"]}`)
+
+	if err := exec.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := exec.Output().(task.ExecOutput)
+	if out.ExitCode != 0 || out.Stdout != "hello\n" {
+		t.Errorf("unexpected output: %+v", out)
+	}
+}
+
+// TestExecTask_ExitCode checks that a non-zero exit code fails the task and stderr is captured.
+func TestExecTask_ExitCode(t *testing.T) {
+	factory := &task.ExecTaskFactory{AllowedCommands: []string{"sh"}, Env: testEnv}
+	exec := newExecTask(t, factory, `{"command":"sh","args":["-c","echo oops >&2; exit 3"]}`)
+
+	if err := exec.Run(context.Background()); err == nil {
+		t.Fatal("expected error for non-zero exit code")
+	}
+
+	out := exec.Output().(task.ExecOutput)
+	if out.ExitCode != 3 || out.Stderr != "oops\n" {
+		t.Errorf("unexpected output: %+v", out)
+	}
+}
+
+// TestExecTask_OutputLimit checks that captured output is truncated to the configured size.
+func TestExecTask_OutputLimit(t *testing.T) {
+	factory := &task.ExecTaskFactory{AllowedCommands: []string{"echo"}, Env: testEnv, MaxOutputBytes: 4}
+	exec := newExecTask(t, factory, `{"command":"echo","args":["hello world"]}`)
+
+	if err := exec.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := exec.Output().(task.ExecOutput)
+	if out.Stdout != "hell" || !out.StdoutTruncated {
+		t.Errorf("expected truncated stdout 'hell', got %+v", out)
+	}
+}
+
+// TestExecTask_Cancel checks that cancellation kills the whole process group promptly.
+func TestExecTask_Cancel(t *testing.T) {
+	factory := &task.ExecTaskFactory{AllowedCommands: []string{"sh"}, Env: testEnv}
+	exec := newExecTask(t, factory, `{"command":"sh","args":["-c","sleep 30 & sleep 30"]}`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := exec.Run(ctx); err == nil {
+		t.Fatal("expected error for cancelled command")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected prompt cancellation, took %v", elapsed)
+	}
+}
+
+// TestExecTaskFactory_Resolve checks that commands are resolved in the PATH of the configured environment only.
+func TestExecTaskFactory_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "hello")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho hi\n"), 0o700); err != nil {
+		t.Fatalf("cannot write script: %v", err)
+	}
+
+	if err := (&task.ExecTaskFactory{AllowedCommands: []string{"echo"}}).Resolve(); err == nil {
+		t.Error("expected a command to be unresolved without PATH in the environment")
+	}
+	if err := (&task.ExecTaskFactory{AllowedCommands: []string{"hello"}, Env: testEnv}).Resolve(); err == nil {
+		t.Error("expected a command outside the PATH to be unresolved")
+	}
+
+	factory := &task.ExecTaskFactory{AllowedCommands: []string{"hello", "./hello"}, Dir: dir, Env: []string{"PATH=" + dir}}
+	if err := factory.Resolve(); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	for _, command := range factory.AllowedCommands {
+		exec := newExecTask(t, factory, `{"command":"`+command+`"}`)
+		if err := exec.Run(context.Background()); err != nil {
+			t.Fatalf("unexpected error for %s: %v", command, err)
+		}
+		if out := exec.Output().(task.ExecOutput); out.Stdout != "hi\n" {
+			t.Errorf("unexpected output for %s: %+v", command, out)
+		}
+	}
+
+	// A task queued before the command was removed from the allow-list does not run it.
+	exec := newExecTask(t, factory, `{"command":"hello"}`)
+	factory.AllowedCommands = []string{"./hello"}
+	if err := exec.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("expected the command to be refused, got %v", err)
+	}
+}
+
+// TestExecTaskFactory_ValidateParams checks that only allow-listed commands are accepted.
+func TestExecTaskFactory_ValidateParams(t *testing.T) {
+	factory := &task.ExecTaskFactory{AllowedCommands: []string{"echo"}, Env: testEnv}
+
+	cases := map[string]string{
+		"not allowed":   `{"command":"rm","args":["-rf","/"]}`,
+		"missing":       `{}`,
+		"unknown field": `{"command":"echo","env":["A=B"]}`,
+		"invalid json":  `{"command":`,
+	}
+
+	for name, params := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := factory.ValidateParams(json.RawMessage(params))
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if name == "not allowed" && !strings.Contains(err.Error(), "not allowed") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
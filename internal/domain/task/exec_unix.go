@@ -0,0 +1,23 @@
+//go:build unix
+
+package task
+
+import (
+	"io/fs"
+	"os/exec"
+	"syscall"
+)
+
+// isExecutable reports whether any execute permission bit of the file is set.
+func isExecutable(info fs.FileInfo) bool {
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// setProcessGroup starts the command in its own process group
+// and makes cancellation kill the whole group, including child processes.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
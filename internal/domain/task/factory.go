@@ -1,19 +1,116 @@
 package task
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
+	"github.com/kylerqws/task-runner/internal/domain/clock"
 	"github.com/kylerqws/task-runner/internal/domain/model"
 )
 
-// DefaultTaskFactory creates instances of DefaultTask with configured RNG and delay.
+// DefaultTaskFactory creates instances of DefaultTask with a delay drawn uniformly
+// from [MinDelay, MaxDelay] and a failure chance of FailureRate for every task.
+// It is safe for concurrent use.
 type DefaultTaskFactory struct {
-	Rng   *rand.Rand    // Random number generator
-	Delay time.Duration // Execution delay duration
+	MinDelay    time.Duration // Lower bound of the delay
+	MaxDelay    time.Duration // Upper bound of the delay
+	FailureRate float64       // Probability of failure (0..1)
+	Seed        int64         // Seed of the shared generator (0 means time-based)
+	Clock       clock.Clock   // Clock used for delays (system clock if nil)
+
+	mu  sync.Mutex
+	rng *rand.Rand
 }
 
-// New creates a new DefaultTask using the factory's configuration.
+// New creates a new DefaultTask, applying the per-task overrides from the task parameters.
+// The parameters are expected to be validated by ValidateParams beforehand.
 func (f *DefaultTaskFactory) New(task *model.Task) ExecutableTask {
-	return NewDefaultTask(task, f.Rng, f.Delay)
+	var p DefaultParams
+	_ = json.Unmarshal(task.Params, &p)
+
+	rate := f.FailureRate
+	if p.FailureProbability != nil {
+		rate = *p.FailureProbability
+	}
+
+	var delay time.Duration
+	var roll float64
+
+	if p.Seed != nil {
+		delay, roll = f.draw(rand.New(rand.NewSource(*p.Seed)))
+	} else {
+		f.mu.Lock()
+		if f.rng == nil {
+			f.rng = rand.New(rand.NewSource(f.seed()))
+		}
+		delay, roll = f.draw(f.rng)
+		f.mu.Unlock()
+	}
+
+	if p.Delay != "" {
+		delay, _ = time.ParseDuration(p.Delay)
+	}
+
+	fail := roll < rate
+	switch p.Outcome {
+	case DefaultOutcomeDone:
+		fail = false
+	case DefaultOutcomeFailed:
+		fail = true
+	}
+
+	return NewDefaultTask(task, clockOrReal(f.Clock), delay, fail)
+}
+
+// ValidateParams checks the per-task overrides.
+func (f *DefaultTaskFactory) ValidateParams(params json.RawMessage) error {
+	var p DefaultParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.Delay != "" {
+		if d, err := time.ParseDuration(p.Delay); err != nil || d < 0 {
+			return fmt.Errorf("invalid delay %q", p.Delay)
+		}
+	}
+	if p.FailureProbability != nil && (*p.FailureProbability < 0 || *p.FailureProbability > 1) {
+		return errors.New("failure_probability must be between 0 and 1")
+	}
+	if p.Outcome != "" && p.Outcome != DefaultOutcomeDone && p.Outcome != DefaultOutcomeFailed {
+		return fmt.Errorf("invalid outcome %q", p.Outcome)
+	}
+
+	return nil
+}
+
+// draw returns a delay from the configured range and a roll in [0, 1) deciding the outcome.
+func (f *DefaultTaskFactory) draw(rng *rand.Rand) (time.Duration, float64) {
+	delay := f.MinDelay
+	if spread := f.MaxDelay - f.MinDelay; spread > 0 {
+		delay += time.Duration(rng.Int63n(int64(spread) + 1))
+	}
+
+	return delay, rng.Float64()
+}
+
+// seed returns the configured seed or a time-based one.
+func (f *DefaultTaskFactory) seed() int64 {
+	if f.Seed != 0 {
+		return f.Seed
+	}
+
+	return time.Now().UnixNano()
+}
+
+// clockOrReal returns the given clock or the system clock if it is nil.
+func clockOrReal(c clock.Clock) clock.Clock {
+	if c != nil {
+		return c
+	}
+
+	return clock.Real()
 }
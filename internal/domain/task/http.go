@@ -0,0 +1,336 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/clock"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+const (
+	// httpDefaultMaxBody is the default limit of stored response body bytes.
+	httpDefaultMaxBody = 64 * 1024
+
+	// httpDefaultRetryBackoff is the default delay before the first retry; it doubles on each retry.
+	httpDefaultRetryBackoff = time.Second
+
+	// httpMaxRetryBackoff caps the doubled delay between retries, unless the first delay is longer.
+	httpMaxRetryBackoff = 5 * time.Minute
+)
+
+// ErrAddressNotAllowed is returned when an "http" task would connect to a loopback, link-local,
+// or cloud metadata address. Such requests are not retried.
+var ErrAddressNotAllowed = errors.New("address not allowed")
+
+// metadataAddrs are the cloud metadata service addresses outside the link-local ranges.
+var metadataAddrs = []netip.Addr{
+	netip.MustParseAddr("fd00:ec2::254"),   // AWS (IPv6)
+	netip.MustParseAddr("100.100.100.200"), // Alibaba Cloud
+}
+
+// HTTPParams holds the parameters of an "http" task taken from the task payload.
+type HTTPParams struct {
+	Method     string            `json:"method,omitempty"`      // HTTP method (GET by default)
+	URL        string            `json:"url"`                   // Target URL on an allow-listed host
+	Headers    map[string]string `json:"headers,omitempty"`     // Request headers
+	Body       string            `json:"body,omitempty"`        // Request body
+	Timeout    string            `json:"timeout,omitempty"`     // Overall task timeout (e.g. "30s")
+	MaxRetries *int              `json:"max_retries,omitempty"` // Retries on 5xx and transport errors
+}
+
+// HTTPOutput holds the structured result of an "http" task.
+type HTTPOutput struct {
+	StatusCode    int                 `json:"status_code,omitempty"`    // Status code of the last response
+	Headers       map[string][]string `json:"headers,omitempty"`        // Headers of the last response
+	Body          string              `json:"body,omitempty"`           // Body of the last response (truncated)
+	BodyTruncated bool                `json:"body_truncated,omitempty"` // Body exceeded the size limit
+	Attempts      int                 `json:"attempts"`                 // Number of requests sent
+}
+
+// HTTPTask sends an HTTP request and records the response.
+type HTTPTask struct {
+	factory *HTTPTaskFactory
+	params  HTTPParams
+	output  HTTPOutput
+}
+
+// Run sends the request, retrying on 5xx responses and transport errors with exponential backoff.
+// A final status code of 400 or above is reported as an error.
+func (t *HTTPTask) Run(ctx context.Context) error {
+	clk := clockOrReal(t.factory.Clock)
+
+	if timeout, _ := time.ParseDuration(t.params.Timeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, clk, timeout)
+		defer cancel()
+	}
+
+	retries := t.factory.retries(t.params.MaxRetries)
+
+	for attempt := 0; ; attempt++ {
+		status, err := t.send(ctx)
+		t.output.Attempts = attempt + 1
+
+		retryable := (err != nil && !errors.Is(err, ErrAddressNotAllowed)) || status >= http.StatusInternalServerError
+		if !retryable || attempt >= retries || ctx.Err() != nil {
+			if err != nil {
+				return err
+			}
+			if status >= http.StatusBadRequest {
+				return fmt.Errorf("unexpected status code %d", status)
+			}
+			return nil
+		}
+
+		if err := clock.Sleep(ctx, clk, t.factory.retryDelay(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+// send performs a single request and stores the response in the task output.
+func (t *HTTPTask) send(ctx context.Context) (int, error) {
+	method := t.params.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.params.URL, strings.NewReader(t.params.Body))
+	if err != nil {
+		return 0, fmt.Errorf("cannot build request: %w", err)
+	}
+	for k, v := range t.params.Headers {
+		req.Header.Set(k, v)
+	}
+	// The request continues the trace of the task run, unless the params set a traceparent of their own.
+	if tp := tracing.SpanContextFromContext(ctx).Traceparent(); tp != "" && req.Header.Get(tracing.TraceparentHeader) == "" {
+		req.Header.Set(tracing.TraceparentHeader, tp)
+	}
+
+	resp, err := t.factory.client().Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	limit := t.factory.maxBody()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return 0, fmt.Errorf("cannot read response body: %w", err)
+	}
+
+	t.output.StatusCode = resp.StatusCode
+	t.output.Headers = resp.Header
+	t.output.BodyTruncated = len(body) > limit
+	t.output.Body = string(body[:min(len(body), limit)])
+
+	return resp.StatusCode, nil
+}
+
+// Output returns the status code, headers, and body of the last response.
+func (t *HTTPTask) Output() any {
+	return t.output
+}
+
+// HTTPTaskFactory creates instances of HTTPTask restricted to the configured hosts.
+// Unless Client sets a transport of its own, connections are checked against the address dialed,
+// so that an allowed host name resolving to a loopback, link-local, or metadata address is refused.
+// Private network addresses are allowed, as internal services are the usual targets.
+type HTTPTaskFactory struct {
+	AllowedHosts  []string      // Host names tasks may call ("*.example.com" matches subdomains)
+	AllowLoopback bool          // Allow connections to loopback addresses
+	MaxBodyBytes  int           // Max stored response body bytes
+	MaxRetries    int           // Max retries on 5xx; also the cap for the per-task value
+	RetryBackoff  time.Duration // Delay before the first retry, doubled on each retry
+	Client        *http.Client  // HTTP client (optional)
+	Clock         clock.Clock   // Clock used for timeouts and backoff (system clock if nil)
+
+	transportOnce sync.Once         // Guards transport
+	transport     http.RoundTripper // Default transport checking the dialed addresses
+}
+
+// New creates a new HTTPTask from the task parameters.
+// The parameters are expected to be validated by ValidateParams beforehand.
+func (f *HTTPTaskFactory) New(task *model.Task) ExecutableTask {
+	t := &HTTPTask{factory: f}
+	_ = json.Unmarshal(task.Params, &t.params)
+
+	return t
+}
+
+// ValidateParams checks that the payload targets an allow-listed host over HTTP(S).
+func (f *HTTPTaskFactory) ValidateParams(params json.RawMessage) error {
+	var p HTTPParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.URL == "" {
+		return errors.New("url is required")
+	}
+	if err := f.checkURL(p.URL); err != nil {
+		return err
+	}
+	if p.Timeout != "" {
+		if d, err := time.ParseDuration(p.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q", p.Timeout)
+		}
+	}
+	if p.MaxRetries != nil && *p.MaxRetries < 0 {
+		return errors.New("max_retries must not be negative")
+	}
+
+	return nil
+}
+
+// checkURL returns an error unless the URL uses HTTP(S) and points to an allow-listed host.
+func (f *HTTPTaskFactory) checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url scheme %q is not allowed", u.Scheme)
+	}
+	if !f.hostAllowed(u.Hostname()) {
+		return fmt.Errorf("host %q is not allowed", u.Hostname())
+	}
+
+	return nil
+}
+
+// hostAllowed reports whether the host matches an entry of the allow-list.
+func (f *HTTPTaskFactory) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+
+	for _, allowed := range f.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok && strings.HasSuffix(host, suffix) {
+			return true
+		}
+		if host == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// client returns the configured HTTP client with redirects restricted to allow-listed hosts.
+func (f *HTTPTaskFactory) client() *http.Client {
+	c := http.Client{}
+	if f.Client != nil {
+		c = *f.Client
+	}
+	if c.Transport == nil {
+		c.Transport = f.defaultTransport()
+	}
+
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return f.checkURL(req.URL.String())
+	}
+
+	return &c
+}
+
+// defaultTransport returns the transport shared by the tasks of the factory:
+// the default transport of the http package with the dialed addresses checked by checkAddr.
+func (f *HTTPTaskFactory) defaultTransport() http.RoundTripper {
+	f.transportOnce.Do(func() {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				return f.checkAddr(address)
+			},
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil // A proxy would dial the target itself, past the address check
+		transport.DialContext = dialer.DialContext
+		f.transport = transport
+	})
+
+	return f.transport
+}
+
+// checkAddr returns ErrAddressNotAllowed if the dialed address is a loopback (unless allowed),
+// link-local, unspecified, multicast, or cloud metadata address.
+func (f *HTTPTaskFactory) checkAddr(address string) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	addr := ap.Addr().Unmap()
+	switch {
+	case addr.IsLoopback() && f.AllowLoopback:
+		return nil
+	case addr.IsLoopback(), addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast(),
+		addr.IsUnspecified(), addr.IsMulticast(), slices.Contains(metadataAddrs, addr):
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, addr)
+	}
+
+	return nil
+}
+
+// retries returns the number of retries for a task, capped by the factory limit.
+func (f *HTTPTaskFactory) retries(requested *int) int {
+	if requested == nil {
+		return f.MaxRetries
+	}
+
+	return min(*requested, f.MaxRetries)
+}
+
+// backoff returns the configured delay before the first retry or the default one.
+func (f *HTTPTaskFactory) backoff() time.Duration {
+	if f.RetryBackoff > 0 {
+		return f.RetryBackoff
+	}
+
+	return httpDefaultRetryBackoff
+}
+
+// retryDelay returns the delay before the retry following the given attempt (0 for the first one):
+// the backoff doubled on each retry, capped by httpMaxRetryBackoff or by the backoff itself if longer.
+func (f *HTTPTaskFactory) retryDelay(attempt int) time.Duration {
+	backoff := f.backoff()
+	limit := max(backoff, httpMaxRetryBackoff)
+
+	// Doubling stops at the cap, so the delay cannot overflow however many retries are allowed.
+	delay := backoff
+	for range attempt {
+		if delay >= limit {
+			break
+		}
+		delay *= 2
+	}
+
+	return min(delay, limit)
+}
+
+// maxBody returns the configured response body limit or the default one.
+func (f *HTTPTaskFactory) maxBody() int {
+	if f.MaxBodyBytes > 0 {
+		return f.MaxBodyBytes
+	}
+
+	return httpDefaultMaxBody
+}
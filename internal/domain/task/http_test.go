@@ -0,0 +1,299 @@
+package task_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/clock"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// newHTTPTask builds an http task from the given parameters after validating them.
+func newHTTPTask(t *testing.T, factory *task.HTTPTaskFactory, params string) *task.HTTPTask {
+	t.Helper()
+	if err := factory.ValidateParams(json.RawMessage(params)); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+
+	meta := model.NewTask("test", task.HTTPTaskType, time.Now())
+	meta.Params = json.RawMessage(params)
+
+	return factory.New(meta).(*task.HTTPTask)
+}
+
+// newTestFactory returns a factory allowing the local test server with fast retries.
+func newTestFactory() *task.HTTPTaskFactory {
+	return &task.HTTPTaskFactory{
+		AllowedHosts:  []string{"127.0.0.1"},
+		AllowLoopback: true,
+		MaxRetries:    2,
+		RetryBackoff:  time.Millisecond,
+	}
+}
+
+// TestHTTPTask_Success checks that the request is sent as specified and the response recorded.
+func TestHTTPTask_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Echo", r.Header.Get("X-Token"))
+		_, _ = fmt.Fprintf(w, "%s %s", r.Method, body)
+	}))
+	defer server.Close()
+
+	params := fmt.Sprintf(`{"method":"POST","url":%q,"headers":{"X-Token":"abc"},"body":"ping"}`, server.URL)
+	tsk := newHTTPTask(t, newTestFactory(), params)
+
+	if err := tsk.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := tsk.Output().(task.HTTPOutput)
+	if out.StatusCode != http.StatusOK || out.Body != "POST ping" || out.Attempts != 1 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	if got := out.Headers["X-Echo"]; len(got) != 1 || got[0] != "abc" {
+		t.Errorf("expected echoed header 'abc', got %v", got)
+	}
+}
+
+// TestHTTPTask_Traceparent checks that the request continues the trace of the task run.
+func TestHTTPTask_Traceparent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(tracing.TraceparentHeader)
+	}))
+	defer server.Close()
+
+	ctx, span := tracing.NewTracer(tracing.NewInMemoryExporter()).Start(context.Background(), "task.run")
+	tsk := newHTTPTask(t, newTestFactory(), fmt.Sprintf(`{"url":%q}`, server.URL))
+
+	if err := tsk.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := span.SpanContext().Traceparent(); got != want {
+		t.Errorf("expected traceparent %q, got %q", want, got)
+	}
+}
+
+// TestHTTPTask_RetryOn5xx checks that 5xx responses are retried until success.
+func TestHTTPTask_RetryOn5xx(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	tsk := newHTTPTask(t, newTestFactory(), fmt.Sprintf(`{"url":%q}`, server.URL))
+
+	if err := tsk.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := tsk.Output().(task.HTTPOutput); out.Attempts != 3 || out.Body != "ok" {
+		t.Errorf("expected success after 3 attempts, got %+v", out)
+	}
+}
+
+// TestHTTPTask_RetriesExhausted checks that persistent 5xx responses fail the task.
+func TestHTTPTask_RetriesExhausted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tsk := newHTTPTask(t, newTestFactory(), fmt.Sprintf(`{"url":%q,"max_retries":1}`, server.URL))
+
+	if err := tsk.Run(context.Background()); err == nil {
+		t.Fatal("expected error after exhausted retries")
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+// TestHTTPTask_BackoffCap checks that the delay between retries stops doubling at 5 minutes.
+func TestHTTPTask_BackoffCap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	fake := clock.NewFake(time.Now())
+	start := fake.Now()
+	factory := newTestFactory()
+	factory.MaxRetries = 70
+	factory.Clock = fake
+	tsk := newHTTPTask(t, factory, fmt.Sprintf(`{"url":%q}`, server.URL))
+
+	done := make(chan error, 1)
+	go func() { done <- tsk.Run(context.Background()) }()
+
+	// Every retry waits for at most one step, so the clock moves by one step per retry.
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case <-done:
+			if out := tsk.Output().(task.HTTPOutput); out.Attempts != 71 {
+				t.Fatalf("expected 71 attempts, got %d", out.Attempts)
+			}
+			if elapsed := fake.Since(start); elapsed != 70*5*time.Minute {
+				t.Errorf("expected retries to wait 5 minutes each at most, waited %v in total", elapsed)
+			}
+			return
+		case <-deadline:
+			t.Fatal("retries did not finish, a delay exceeded 5 minutes")
+		default:
+		}
+		if fake.Waiters() > 0 {
+			fake.Advance(5 * time.Minute)
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+}
+
+// TestHTTPTask_NoRetryOn4xx checks that client errors fail the task without retries.
+func TestHTTPTask_NoRetryOn4xx(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tsk := newHTTPTask(t, newTestFactory(), fmt.Sprintf(`{"url":%q}`, server.URL))
+
+	if err := tsk.Run(context.Background()); err == nil {
+		t.Fatal("expected error for 404 response")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
+
+// TestHTTPTask_Timeout checks that the per-task timeout aborts a slow request.
+func TestHTTPTask_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	tsk := newHTTPTask(t, newTestFactory(), fmt.Sprintf(`{"url":%q,"timeout":"50ms"}`, server.URL))
+
+	start := time.Now()
+	if err := tsk.Run(context.Background()); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected prompt timeout, took %v", elapsed)
+	}
+}
+
+// TestHTTPTask_BodyLimit checks that the stored response body is truncated.
+func TestHTTPTask_BodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "0123456789")
+	}))
+	defer server.Close()
+
+	factory := newTestFactory()
+	factory.MaxBodyBytes = 4
+	tsk := newHTTPTask(t, factory, fmt.Sprintf(`{"url":%q}`, server.URL))
+
+	if err := tsk.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := tsk.Output().(task.HTTPOutput); out.Body != "0123" || !out.BodyTruncated {
+		t.Errorf("expected truncated body '0123', got %+v", out)
+	}
+}
+
+// TestHTTPTask_RedirectToForeignHost checks that redirects cannot escape the host allow-list.
+func TestHTTPTask_RedirectToForeignHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+
+	factory := newTestFactory()
+	factory.MaxRetries = 0
+	tsk := newHTTPTask(t, factory, fmt.Sprintf(`{"url":%q}`, server.URL))
+
+	if err := tsk.Run(context.Background()); err == nil {
+		t.Fatal("expected error for redirect to a foreign host")
+	}
+}
+
+// TestHTTPTask_AddressNotAllowed checks that allowed hosts resolving to loopback or metadata addresses
+// are refused when dialed, without retries.
+func TestHTTPTask_AddressNotAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	factory := &task.HTTPTaskFactory{
+		AllowedHosts: []string{"localhost", "127.0.0.1", "169.254.169.254"},
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	}
+	for _, u := range []string{
+		fmt.Sprintf("http://localhost:%d/", port),
+		fmt.Sprintf("http://127.0.0.1:%d/", port),
+		"http://169.254.169.254/latest/meta-data",
+	} {
+		tsk := newHTTPTask(t, factory, fmt.Sprintf(`{"url":%q}`, u))
+
+		if err := tsk.Run(context.Background()); !errors.Is(err, task.ErrAddressNotAllowed) {
+			t.Errorf("expected ErrAddressNotAllowed for %s, got %v", u, err)
+		}
+		if out := tsk.Output().(task.HTTPOutput); out.Attempts != 1 {
+			t.Errorf("expected 1 attempt for %s, got %d", u, out.Attempts)
+		}
+	}
+}
+
+// TestHTTPTaskFactory_ValidateParams checks that only allow-listed hosts and schemes are accepted.
+func TestHTTPTaskFactory_ValidateParams(t *testing.T) {
+	factory := &task.HTTPTaskFactory{AllowedHosts: []string{"api.internal", "*.svc.local"}}
+
+	valid := []string{
+		`{"url":"http://api.internal/jobs"}`,
+		`{"url":"https://billing.svc.local:8443/run","method":"POST"}`,
+	}
+	for _, params := range valid {
+		if err := factory.ValidateParams(json.RawMessage(params)); err != nil {
+			t.Errorf("expected %s to be valid, got %v", params, err)
+		}
+	}
+
+	invalid := []string{
+		`{"url":"http://169.254.169.254/latest/meta-data"}`,
+		`{"url":"http://api.internal.evil.com/"}`,
+		`{"url":"file:///etc/passwd"}`,
+		`{"url":"http://api.internal/","timeout":"soon"}`,
+		`{"url":"http://api.internal/","max_retries":-1}`,
+		`{"method":"GET"}`,
+	}
+	for _, params := range invalid {
+		if err := factory.ValidateParams(json.RawMessage(params)); err == nil {
+			t.Errorf("expected %s to be rejected", params)
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package task
 
-import "github.com/kylerqws/task-runner/internal/domain/model"
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
 
 // ExecutableTask defines the behavior of a task that can be executed.
 type ExecutableTask interface {
 	// Run executes the task logic and returns an error if it fails.
-	Run() error
+	// The context is cancelled when the task exceeds its timeout.
+	Run(ctx context.Context) error
 }
 
 // Factory defines an interface for creating tasks of a specific type.
@@ -13,3 +19,16 @@ type Factory interface {
 	// New creates a new ExecutableTask based on the provided Task metadata.
 	New(task *model.Task) ExecutableTask
 }
+
+// ParamsValidator is implemented by factories that validate task parameters
+// before a task is accepted into the queue.
+type ParamsValidator interface {
+	// ValidateParams returns an error if the parameters are not acceptable.
+	ValidateParams(params json.RawMessage) error
+}
+
+// OutputTask is implemented by tasks that produce a structured output.
+type OutputTask interface {
+	// Output returns the structured result of the task. It is called after Run returns.
+	Output() any
+}
@@ -3,4 +3,49 @@ package task
 const (
 	// DefaultTaskType is the identifier used to register and trigger the default task type.
 	DefaultTaskType = "default"
+
+	// ExecTaskType is the identifier of the task type running allow-listed shell commands.
+	ExecTaskType = "exec"
+
+	// HTTPTaskType is the identifier of the task type sending HTTP requests to allow-listed hosts.
+	HTTPTaskType = "http"
+)
+
+const (
+	// DefaultTaskDescription describes the default task type for discovery.
+	DefaultTaskDescription = "Simulates a long-running job with a random delay and failure chance."
+
+	// DefaultTaskParamsSchema is the JSON schema of the default task parameters.
+	DefaultTaskParamsSchema = `{"type":"object","properties":{` +
+		`"delay":{"type":"string"},` +
+		`"failure_probability":{"type":"number","minimum":0,"maximum":1},` +
+		`"seed":{"type":"integer"},` +
+		`"outcome":{"type":"string","enum":["done","failed"]}` +
+		`},"additionalProperties":false}`
+)
+
+const (
+	// ExecTaskDescription describes the exec task type for discovery.
+	ExecTaskDescription = "Runs an allow-listed command and captures its exit code and output."
+
+	// ExecTaskParamsSchema is the JSON schema of the exec task parameters.
+	ExecTaskParamsSchema = `{"type":"object","properties":{` +
+		`"command":{"type":"string"},` +
+		`"args":{"type":"array","items":{"type":"string"}}` +
+		`},"required":["command"],"additionalProperties":false}`
+)
+
+const (
+	// HTTPTaskDescription describes the http task type for discovery.
+	HTTPTaskDescription = "Sends an HTTP request to an allow-listed host and records the response."
+
+	// HTTPTaskParamsSchema is the JSON schema of the http task parameters.
+	HTTPTaskParamsSchema = `{"type":"object","properties":{` +
+		`"method":{"type":"string"},` +
+		`"url":{"type":"string","format":"uri"},` +
+		`"headers":{"type":"object","additionalProperties":{"type":"string"}},` +
+		`"body":{"type":"string"},` +
+		`"timeout":{"type":"string"},` +
+		`"max_retries":{"type":"integer","minimum":0}` +
+		`},"required":["url"],"additionalProperties":false}`
 )
@@ -0,0 +1,68 @@
+// Package logging builds the structured logger of the application
+// and carries the request ID from the HTTP layer to the logs of the domain.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Output formats of New.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ErrInvalidFormat is returned when the log format is neither "text" nor "json".
+var ErrInvalidFormat = errors.New("invalid log format")
+
+// New returns a logger writing to w in the given format ("text" or "json", default "text")
+// and dropping records below the given level ("debug", "info", "warn", or "error", default "info").
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("cannot create logger with format %q: %w", format, ErrInvalidFormat)
+	}
+}
+
+// ParseLevel parses a level name ("debug", "info", "warn", or "error"); an empty name means "info".
+func ParseLevel(level string) (slog.Level, error) {
+	var lvl slog.Level
+	if level == "" {
+		return lvl, nil
+	}
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return lvl, fmt.Errorf("cannot parse log level %q: %w", level, err)
+	}
+
+	return lvl, nil
+}
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being served.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,48 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/logging"
+)
+
+// TestNew checks the output format and level of the logger, and the rejection of unknown values.
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "warn")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	logger.Info("dropped")
+	logger.Warn("kept", "task_id", "abc")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "kept" || entry["task_id"] != "abc" {
+		t.Errorf("unexpected record %v", entry)
+	}
+
+	if _, err := logging.New(&buf, "xml", ""); !errors.Is(err, logging.ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+	if _, err := logging.New(&buf, "", "loud"); err == nil {
+		t.Error("expected unknown level to fail")
+	}
+}
+
+// TestRequestID checks that the request ID round-trips through a context.
+func TestRequestID(t *testing.T) {
+	if id := logging.RequestID(context.Background()); id != "" {
+		t.Errorf("expected no request ID, got %q", id)
+	}
+	if id := logging.RequestID(logging.WithRequestID(context.Background(), "req-1")); id != "req-1" {
+		t.Errorf("expected req-1, got %q", id)
+	}
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Exporter receives the finished spans of a tracer.
+type Exporter interface {
+	// Export takes a finished span. It must not block for long, since it is called
+	// when the span ends, possibly with locks held.
+	Export(span SpanData)
+	// Shutdown sends the spans still buffered and stops the exporter.
+	Shutdown(ctx context.Context) error
+}
+
+// InMemoryExporter keeps the exported spans in memory, e.g. for tests.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter returns an empty in-memory exporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// Export stores the span.
+func (e *InMemoryExporter) Export(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+}
+
+// Shutdown does nothing.
+func (e *InMemoryExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// Spans returns the spans exported so far, in the order they ended.
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]SpanData{}, e.spans...)
+}
+
+// WriterExporter writes every span as a line of JSON, e.g. to stdout.
+type WriterExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterExporter returns an exporter writing the spans to w.
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{enc: json.NewEncoder(w)}
+}
+
+// Export writes the span in the OTLP JSON span format. Write errors are ignored.
+func (e *WriterExporter) Export(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_ = e.enc.Encode(newOTLPSpan(span))
+}
+
+// Shutdown does nothing.
+func (e *WriterExporter) Shutdown(_ context.Context) error {
+	return nil
+}
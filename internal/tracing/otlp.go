@@ -0,0 +1,327 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults of OTLPExporter.
+const (
+	DefaultOTLPEndpoint  = "http://localhost:4318/v1/traces"
+	DefaultFlushInterval = 5 * time.Second
+	DefaultServiceName   = "task-runner"
+
+	maxBatchSize   = 512  // Spans sent in one request
+	maxQueuedSpans = 4096 // Spans buffered before new ones are dropped
+	scopeName      = "github.com/kylerqws/task-runner"
+)
+
+// OTLPExporter buffers spans and sends them in batches to an OTLP/HTTP collector, encoded as JSON.
+// Batches are sent every flush interval, when they are full, and on Shutdown.
+// Failed batches are logged and dropped, and spans beyond the buffer limit are dropped.
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	headers     map[string]string
+	interval    time.Duration
+	client      *http.Client
+
+	mu      sync.Mutex
+	queue   []SpanData
+	dropped int
+
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// OTLPOption configures an OTLPExporter.
+type OTLPOption func(*OTLPExporter)
+
+// WithServiceName sets the service.name resource attribute of the spans (DefaultServiceName by default).
+func WithServiceName(name string) OTLPOption {
+	return func(e *OTLPExporter) {
+		if name != "" {
+			e.serviceName = name
+		}
+	}
+}
+
+// WithHeaders sets headers sent with every request, e.g. credentials of the collector.
+func WithHeaders(headers map[string]string) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.headers = headers
+	}
+}
+
+// WithFlushInterval sets how often buffered spans are sent (DefaultFlushInterval by default).
+func WithFlushInterval(interval time.Duration) OTLPOption {
+	return func(e *OTLPExporter) {
+		if interval > 0 {
+			e.interval = interval
+		}
+	}
+}
+
+// WithHTTPClient sets the client sending the spans.
+func WithHTTPClient(client *http.Client) OTLPOption {
+	return func(e *OTLPExporter) {
+		if client != nil {
+			e.client = client
+		}
+	}
+}
+
+// NewOTLPExporter returns an exporter sending spans to the OTLP/HTTP traces endpoint
+// (DefaultOTLPEndpoint if empty) and starts its background sender.
+func NewOTLPExporter(endpoint string, opts ...OTLPOption) *OTLPExporter {
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+
+	e := &OTLPExporter{
+		endpoint:    endpoint,
+		serviceName: DefaultServiceName,
+		interval:    DefaultFlushInterval,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		flush:       make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	go e.loop()
+
+	return e
+}
+
+// Export buffers the span and wakes up the sender once a batch is full.
+func (e *OTLPExporter) Export(span SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.queue) >= maxQueuedSpans {
+		e.dropped++
+		return
+	}
+
+	e.queue = append(e.queue, span)
+	if len(e.queue) >= maxBatchSize {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Shutdown stops the sender and sends the buffered spans until ctx is done.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.once.Do(func() { close(e.stop) })
+	<-e.done
+
+	for {
+		batch := e.take()
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := e.send(ctx, batch); err != nil {
+			return fmt.Errorf("cannot flush spans: %w", err)
+		}
+	}
+}
+
+// loop sends the buffered spans every interval and whenever a batch is full, until Shutdown.
+func (e *OTLPExporter) loop() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+		case <-e.flush:
+		}
+
+		for batch := e.take(); len(batch) > 0; batch = e.take() {
+			if err := e.send(context.Background(), batch); err != nil {
+				slog.Warn("Cannot export spans", "spans", len(batch), "error", err)
+				break
+			}
+		}
+	}
+}
+
+// take removes and returns the next batch of buffered spans.
+func (e *OTLPExporter) take() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.dropped > 0 {
+		slog.Warn("Spans dropped, export buffer full", "spans", e.dropped)
+		e.dropped = 0
+	}
+
+	n := min(len(e.queue), maxBatchSize)
+	batch := e.queue[:n:n]
+	e.queue = e.queue[n:]
+
+	return batch
+}
+
+// send posts a batch of spans to the collector.
+func (e *OTLPExporter) send(ctx context.Context, batch []SpanData) error {
+	body, err := json.Marshal(newOTLPRequest(e.serviceName, batch))
+	if err != nil {
+		return fmt.Errorf("cannot encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create request to %q: %w", e.endpoint, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot send spans to %q: %w", e.endpoint, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("cannot send spans to %q: status %s", e.endpoint, resp.Status)
+	}
+
+	return nil
+}
+
+// OTLP JSON encoding of spans (opentelemetry/proto/collector/trace/v1, JSON mapping).
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              SpanKind       `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Links             []otlpLink     `json:"links,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpLink struct {
+		TraceID string `json:"traceId"`
+		SpanID  string `json:"spanId"`
+	}
+	otlpStatus struct {
+		Code    StatusCode `json:"code"`
+		Message string     `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+// newOTLPRequest returns the export request of the spans of a service.
+func newOTLPRequest(serviceName string, spans []SpanData) otlpRequest {
+	encoded := make([]otlpSpan, len(spans))
+	for i, s := range spans {
+		encoded[i] = newOTLPSpan(s)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpKeyValue{newOTLPKeyValue(slog.String("service.name", serviceName))}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: scopeName}, Spans: encoded}},
+	}}}
+}
+
+// newOTLPSpan encodes a span.
+func newOTLPSpan(s SpanData) otlpSpan {
+	span := otlpSpan{
+		TraceID:           s.SpanContext.TraceID.String(),
+		SpanID:            s.SpanContext.SpanID.String(),
+		Name:              s.Name,
+		Kind:              s.Kind,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+		Status:            otlpStatus{Code: s.Status, Message: s.StatusMessage},
+	}
+	if s.Parent.IsValid() {
+		span.ParentSpanID = s.Parent.SpanID.String()
+	}
+	for _, a := range s.Attributes {
+		span.Attributes = append(span.Attributes, newOTLPKeyValue(a))
+	}
+	for _, l := range s.Links {
+		span.Links = append(span.Links, otlpLink{TraceID: l.TraceID.String(), SpanID: l.SpanID.String()})
+	}
+
+	return span
+}
+
+// newOTLPKeyValue encodes an attribute; kinds without an OTLP equivalent are encoded as strings.
+func newOTLPKeyValue(a slog.Attr) otlpKeyValue {
+	kv := otlpKeyValue{Key: a.Key}
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindBool:
+		b := v.Bool()
+		kv.Value.BoolValue = &b
+	case slog.KindInt64:
+		i := strconv.FormatInt(v.Int64(), 10)
+		kv.Value.IntValue = &i
+	case slog.KindUint64:
+		i := strconv.FormatUint(v.Uint64(), 10)
+		kv.Value.IntValue = &i
+	case slog.KindFloat64:
+		f := v.Float64()
+		kv.Value.DoubleValue = &f
+	default:
+		s := v.String()
+		kv.Value.StringValue = &s
+	}
+
+	return kv
+}
@@ -0,0 +1,263 @@
+// Package tracing records spans of HTTP requests and task execution, propagates them
+// with W3C Trace Context, and exports them via OTLP or to a writer.
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SpanKind is the role of a span in a trace, with the values of OTLP.
+type SpanKind int
+
+// Kinds of spans.
+const (
+	SpanKindInternal SpanKind = 1 // Internal operation
+	SpanKindServer   SpanKind = 2 // Incoming request
+	SpanKindConsumer SpanKind = 5 // Processing of a queued message
+)
+
+// StatusCode is the outcome of a span, with the values of OTLP.
+type StatusCode int
+
+// Outcomes of spans.
+const (
+	StatusUnset StatusCode = 0 // Not set, the operation is assumed to have succeeded
+	StatusOK    StatusCode = 1 // Explicitly successful
+	StatusError StatusCode = 2 // Failed
+)
+
+// SpanData is a finished span as passed to exporters.
+type SpanData struct {
+	Name          string        // Operation name
+	Kind          SpanKind      // Role of the span
+	SpanContext   SpanContext   // IDs of the span
+	Parent        SpanContext   // Parent span (zero for a root span)
+	Links         []SpanContext // Related spans outside the parent chain
+	Start         time.Time     // Start of the operation
+	End           time.Time     // End of the operation
+	Attributes    []slog.Attr   // Properties of the operation
+	Status        StatusCode    // Outcome
+	StatusMessage string        // Description of an error
+}
+
+// Tracer starts spans and passes the finished, sampled ones to its exporter.
+// A nil *Tracer starts no spans, so tracing can be disabled by not creating one.
+type Tracer struct {
+	exporter Exporter
+}
+
+// NewTracer returns a tracer exporting its spans to the exporter.
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// Shutdown flushes the spans still buffered by the exporter and stops it.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.exporter.Shutdown(ctx)
+}
+
+// spanConfig holds the settings of a span being started.
+type spanConfig struct {
+	kind   SpanKind
+	start  time.Time
+	parent *SpanContext
+	links  []SpanContext
+	attrs  []slog.Attr
+}
+
+// SpanOption configures a span being started.
+type SpanOption func(*spanConfig)
+
+// WithKind sets the kind of the span (internal by default).
+func WithKind(kind SpanKind) SpanOption {
+	return func(c *spanConfig) {
+		c.kind = kind
+	}
+}
+
+// WithStartTime sets the start of the span (now by default).
+func WithStartTime(start time.Time) SpanOption {
+	return func(c *spanConfig) {
+		c.start = start
+	}
+}
+
+// WithParent sets the parent of the span instead of the one in the context.
+// An invalid span context makes the span the root of a new trace.
+func WithParent(parent SpanContext) SpanOption {
+	return func(c *spanConfig) {
+		c.parent = &parent
+	}
+}
+
+// WithLinks links the span to related spans.
+func WithLinks(links ...SpanContext) SpanOption {
+	return func(c *spanConfig) {
+		for _, l := range links {
+			if l.IsValid() {
+				c.links = append(c.links, l)
+			}
+		}
+	}
+}
+
+// WithAttributes sets attributes of the span.
+func WithAttributes(attrs ...slog.Attr) SpanOption {
+	return func(c *spanConfig) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// Start starts a span and returns it with a copy of ctx carrying it.
+// The parent is the span of ctx (local or remote) unless set with WithParent; the span is sampled
+// if its parent is, and always when it starts a new trace. On a nil tracer, ctx and a nil span are returned.
+func (t *Tracer) Start(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	cfg := spanConfig{kind: SpanKindInternal}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.start.IsZero() {
+		cfg.start = time.Now()
+	}
+
+	parent := SpanContextFromContext(ctx)
+	if cfg.parent != nil {
+		parent = *cfg.parent
+	}
+
+	sc := SpanContext{TraceID: parent.TraceID, SpanID: newSpanID(), Sampled: parent.Sampled}
+	if !parent.IsValid() {
+		parent = SpanContext{}
+		sc.TraceID, sc.Sampled = newTraceID(), true
+	}
+
+	s := &Span{tracer: t, data: SpanData{
+		Name:        name,
+		Kind:        cfg.kind,
+		SpanContext: sc,
+		Parent:      parent,
+		Links:       cfg.links,
+		Start:       cfg.start,
+		Attributes:  cfg.attrs,
+	}}
+
+	return ContextWithSpan(ctx, s), s
+}
+
+// Span is an operation being traced. All methods are safe on a nil span, which records nothing.
+type Span struct {
+	tracer *Tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext returns the IDs of the span, or a zero span context for a nil span.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+
+	return s.data.SpanContext
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...slog.Attr) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// SetStatus sets the outcome of the span, with a description for errors.
+func (s *Span) SetStatus(code StatusCode, msg string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Status = code
+	s.data.StatusMessage = msg
+}
+
+// End ends the span now.
+func (s *Span) End() {
+	s.EndAt(time.Now())
+}
+
+// EndAt ends the span at the given time and exports it if it is sampled.
+// Only the first call has an effect.
+func (s *Span) EndAt(end time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = end
+	data := s.data
+	s.mu.Unlock()
+
+	if data.SpanContext.Sampled {
+		s.tracer.exporter.Export(data)
+	}
+}
+
+type (
+	spanKey       struct{} // spanKey is the context key of the current local span.
+	remoteSpanKey struct{} // remoteSpanKey is the context key of a span context received from another process.
+)
+
+// ContextWithSpan returns a copy of ctx carrying the span as the current one.
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	if s == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+// ContextWithRemoteSpanContext returns a copy of ctx carrying a span context received from another process,
+// e.g. parsed from the traceparent header, to be used as the parent of the next span.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanKey{}, sc)
+}
+
+// SpanFromContext returns the current local span of ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SpanContextFromContext returns the span context of the current span of ctx, local or remote,
+// or a zero span context if there is none.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if s := SpanFromContext(ctx); s != nil {
+		return s.SpanContext()
+	}
+	sc, _ := ctx.Value(remoteSpanKey{}).(SpanContext)
+
+	return sc
+}
@@ -0,0 +1,151 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// TestParseTraceparent checks the parsing of valid and malformed traceparent values.
+func TestParseTraceparent(t *testing.T) {
+	const value = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sc, err := tracing.ParseTraceparent(value)
+	if err != nil {
+		t.Fatalf("ParseTraceparent failed: %v", err)
+	}
+	if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" || !sc.Sampled {
+		t.Errorf("unexpected span context %+v", sc)
+	}
+	if got := sc.Traceparent(); got != value {
+		t.Errorf("expected %q, got %q", value, got)
+	}
+
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, err := tracing.ParseTraceparent(bad); !errors.Is(err, tracing.ErrInvalidTraceparent) {
+			t.Errorf("expected ErrInvalidTraceparent for %q, got %v", bad, err)
+		}
+	}
+}
+
+// TestTracer checks the parent of spans, the sampling decision, and that nil tracers and spans are no-ops.
+func TestTracer(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := tracing.NewTracer(exporter)
+
+	ctx, root := tracer.Start(context.Background(), "root", tracing.WithKind(tracing.SpanKindServer))
+	_, child := tracer.Start(ctx, "child", tracing.WithAttributes(slog.String("k", "v")))
+	child.SetStatus(tracing.StatusError, "boom")
+	child.End()
+	root.End()
+	root.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "child" || spans[0].SpanContext.TraceID != root.SpanContext().TraceID ||
+		spans[0].Parent.SpanID != root.SpanContext().SpanID || spans[0].Status != tracing.StatusError {
+		t.Errorf("unexpected child span %+v", spans[0])
+	}
+	if spans[1].Parent.IsValid() || !spans[1].SpanContext.Sampled {
+		t.Errorf("expected a sampled root span, got %+v", spans[1])
+	}
+
+	unsampled, _ := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	_, span := tracer.Start(tracing.ContextWithRemoteSpanContext(context.Background(), unsampled), "unsampled")
+	span.End()
+	if len(exporter.Spans()) != 2 || span.SpanContext().TraceID != unsampled.TraceID {
+		t.Error("expected the span of an unsampled trace to continue it without being exported")
+	}
+
+	var none *tracing.Tracer
+	ctx, span = none.Start(context.Background(), "nothing")
+	span.SetAttributes(slog.Int("n", 1))
+	span.End()
+	if span != nil || tracing.SpanFromContext(ctx) != nil {
+		t.Error("expected a nil tracer to start no span")
+	}
+}
+
+// TestOTLPExporter checks that spans are sent to the collector as OTLP JSON on shutdown.
+func TestOTLPExporter(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected configured headers, got %v", r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer collector.Close()
+
+	exporter := tracing.NewOTLPExporter(collector.URL,
+		tracing.WithServiceName("test"),
+		tracing.WithHeaders(map[string]string{"Authorization": "Bearer secret"}),
+		tracing.WithFlushInterval(time.Hour),
+	)
+	tracer := tracing.NewTracer(exporter)
+
+	start := time.Unix(100, 0)
+	_, span := tracer.Start(context.Background(), "op", tracing.WithStartTime(start), tracing.WithAttributes(slog.Int("n", 3)))
+	span.EndAt(start.Add(time.Second))
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	var req struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string `json:"key"`
+					Value struct {
+						StringValue string `json:"stringValue"`
+					} `json:"value"`
+				} `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID           string `json:"traceId"`
+					Name              string `json:"name"`
+					StartTimeUnixNano string `json:"startTimeUnixNano"`
+					EndTimeUnixNano   string `json:"endTimeUnixNano"`
+					Attributes        []struct {
+						Key   string `json:"key"`
+						Value struct {
+							IntValue string `json:"intValue"`
+						} `json:"value"`
+					} `json:"attributes"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(<-bodies, &req); err != nil {
+		t.Fatalf("cannot decode export request: %v", err)
+	}
+
+	rs := req.ResourceSpans[0]
+	got := rs.ScopeSpans[0].Spans[0]
+	if rs.Resource.Attributes[0].Value.StringValue != "test" || got.Name != "op" ||
+		got.TraceID != span.SpanContext().TraceID.String() ||
+		got.StartTimeUnixNano != "100000000000" || got.EndTimeUnixNano != "101000000000" ||
+		got.Attributes[0].Key != "n" || got.Attributes[0].Value.IntValue != "3" {
+		t.Errorf("unexpected export request %+v", req)
+	}
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// hashPrefix is the prefix of the API key hashes accepted in the configuration.
+const hashPrefix = "sha256:"
+
+// APIKey is a static API key known by its hash.
+type APIKey struct {
+	Name   string   // Client name, used as the identity subject
+	Hash   string   // "sha256:" followed by the hex SHA-256 digest of the key
+	Scopes []string // Granted scopes
+}
+
+// HashKey returns the hash of an API key in the form accepted by APIKey.Hash.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+// apiKey is an API key with its decoded digest.
+type apiKey struct {
+	digest   []byte
+	identity *Identity
+}
+
+// parseAPIKey decodes the hash of an API key.
+func parseAPIKey(k APIKey) (apiKey, error) {
+	digest, err := hex.DecodeString(strings.TrimPrefix(k.Hash, hashPrefix))
+	if !strings.HasPrefix(k.Hash, hashPrefix) || err != nil || len(digest) != sha256.Size {
+		return apiKey{}, fmt.Errorf("cannot parse hash of API key %q: expected %s<64 hex digits>", k.Name, hashPrefix)
+	}
+
+	return apiKey{digest: digest, identity: &Identity{Subject: k.Name, Scopes: k.Scopes}}, nil
+}
+
+// matchAPIKey returns the identity of the key, comparing digests in constant time.
+func matchAPIKey(keys []apiKey, key string) (*Identity, bool) {
+	sum := sha256.Sum256([]byte(key))
+
+	var found *Identity
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare(k.digest, sum[:]) == 1 {
+			found = k.identity
+		}
+	}
+
+	return found, found != nil
+}
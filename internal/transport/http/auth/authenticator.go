@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/domain/clock"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// APIKeyHeader is the header carrying an API key; a bearer token in the Authorization header works as well.
+const APIKeyHeader = "X-API-Key"
+
+// publicPaths are served without authentication.
+var publicPaths = []string{"/openapi.json"}
+
+// Config lists the credentials accepted by the authenticator.
+type Config struct {
+	APIKeys     []APIKey     // Static API keys
+	JWKSFile    string       // JWKS file with the public keys verifying JWTs (optional)
+	Issuer      string       // Required "iss" claim of JWTs (optional)
+	Audience    string       // Required "aud" claim of JWTs (optional)
+	ClientCerts []ClientCert // Identities of verified TLS client certificates (optional)
+}
+
+// Authenticator identifies API clients by API key or JWT.
+type Authenticator struct {
+	keys     []apiKey
+	jwks     []verificationKey
+	certs    []ClientCert
+	issuer   string
+	audience string
+	clock    clock.Clock
+}
+
+// Option configures an Authenticator.
+type Option func(*Authenticator)
+
+// WithClock sets the clock used to check the expiration of JWTs.
+func WithClock(c clock.Clock) Option {
+	return func(a *Authenticator) {
+		if c != nil {
+			a.clock = c
+		}
+	}
+}
+
+// New returns an Authenticator accepting the configured credentials.
+func New(cfg Config, opts ...Option) (*Authenticator, error) {
+	a := &Authenticator{issuer: cfg.Issuer, audience: cfg.Audience, certs: cfg.ClientCerts, clock: clock.Real()}
+
+	for _, k := range cfg.APIKeys {
+		key, err := parseAPIKey(k)
+		if err != nil {
+			return nil, err
+		}
+		a.keys = append(a.keys, key)
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = keys
+	}
+
+	if len(a.keys) == 0 && len(a.jwks) == 0 && len(a.certs) == 0 {
+		return nil, errors.New("cannot create authenticator: no API keys, JWKS file, or client certificates")
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a, nil
+}
+
+// Authenticate returns the identity of the client sending the request.
+// The credential is read from the X-API-Key header or the bearer token of the Authorization header;
+// a bearer token shaped like a JWT is verified against the JWKS when one is configured.
+// Without either header, the verified TLS client certificate identifies the client.
+func (a *Authenticator) Authenticate(r *http.Request) (*Identity, error) {
+	credential := r.Header.Get(APIKeyHeader)
+	if credential == "" {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if strings.EqualFold(scheme, "Bearer") {
+			credential = strings.TrimSpace(token)
+		}
+	}
+	if credential == "" && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return a.matchClientCert(r.TLS.VerifiedChains[0][0])
+	}
+	if credential == "" {
+		return nil, errors.New("missing credentials")
+	}
+
+	if len(a.jwks) > 0 && strings.Count(credential, ".") == 2 {
+		claims, err := a.verifyJWT(credential)
+		if err != nil {
+			return nil, fmt.Errorf("cannot verify token: %w", err)
+		}
+		return claims.identity(), nil
+	}
+
+	if id, ok := matchAPIKey(a.keys, credential); ok {
+		return id, nil
+	}
+
+	return nil, errors.New("unknown API key")
+}
+
+// Middleware rejects unauthenticated requests with a 401 problem response and passes the identity
+// of the others to next through the request context, where it is also the actor of audited operations.
+// Public paths are served as they are.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range publicPaths {
+			if r.URL.Path == path {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		id, err := a.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="task-runner"`)
+			response.RespondProblem(w, r, http.StatusUnauthorized, response.CodeUnauthorized, err.Error())
+			return
+		}
+
+		ctx := audit.WithActor(WithIdentity(r.Context(), id), id.Subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,216 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/domain/clock"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+)
+
+// newJWKS writes a JWKS file with a fresh Ed25519 key and returns its path and the private key.
+func newJWKS(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("cannot generate key: %v", err)
+	}
+
+	doc := map[string]any{"keys": []map[string]string{{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"kid": "k1",
+		"x":   base64.RawURLEncoding.EncodeToString(pub),
+	}}}
+	data, _ := json.Marshal(doc)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("cannot write JWKS: %v", err)
+	}
+
+	return path, priv
+}
+
+// signJWT returns a compact EdDSA JWT with the given claims.
+func signJWT(t *testing.T, key ed25519.PrivateKey, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "EdDSA", "kid": "k1", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(key, []byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// TestAuthenticate_APIKey checks that API keys are matched by hash from either header.
+func TestAuthenticate_APIKey(t *testing.T) {
+	a, err := auth.New(auth.Config{APIKeys: []auth.APIKey{
+		{Name: "ci", Hash: auth.HashKey("secret"), Scopes: []string{auth.ScopeTasksRead}},
+	}})
+	if err != nil {
+		t.Fatalf("cannot create authenticator: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set(auth.APIKeyHeader, "secret")
+	id, err := a.Authenticate(req)
+	if err != nil || id.Subject != "ci" || !id.Has(auth.ScopeTasksRead) {
+		t.Fatalf("expected identity ci, got %+v (%v)", id, err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	if _, err := a.Authenticate(req); err != nil {
+		t.Errorf("expected bearer API key to be accepted, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set(auth.APIKeyHeader, "wrong")
+	if _, err := a.Authenticate(req); err == nil {
+		t.Error("expected unknown API key to be rejected")
+	}
+
+	if _, err := auth.New(auth.Config{APIKeys: []auth.APIKey{{Name: "bad", Hash: "secret"}}}); err == nil {
+		t.Error("expected invalid hash to be rejected")
+	}
+}
+
+// TestAuthenticate_JWT checks the signature, expiration, and audience of JWTs.
+func TestAuthenticate_JWT(t *testing.T) {
+	path, key := newJWKS(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a, err := auth.New(auth.Config{JWKSFile: path, Audience: "task-runner"}, auth.WithClock(clock.NewFake(now)))
+	if err != nil {
+		t.Fatalf("cannot create authenticator: %v", err)
+	}
+
+	_, otherKey := newJWKS(t)
+	valid := map[string]any{"sub": "alice", "aud": []string{"task-runner"}, "exp": now.Add(time.Hour).Unix(), "scope": "tasks:read tasks:create:*"}
+
+	cases := []struct {
+		name   string
+		key    ed25519.PrivateKey
+		claims map[string]any
+		ok     bool
+	}{
+		{"valid", key, valid, true},
+		{"wrong key", otherKey, valid, false},
+		{"expired", key, map[string]any{"sub": "alice", "aud": "task-runner", "exp": now.Add(-time.Hour).Unix()}, false},
+		{"wrong audience", key, map[string]any{"sub": "alice", "aud": "other", "exp": now.Add(time.Hour).Unix()}, false},
+		{"no subject", key, map[string]any{"aud": "task-runner", "exp": now.Add(time.Hour).Unix()}, false},
+		{"no expiry", key, map[string]any{"sub": "alice", "aud": "task-runner", "scope": "tasks:read"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.Header.Set("Authorization", "Bearer "+signJWT(t, tc.key, tc.claims))
+
+			id, err := a.Authenticate(req)
+			if (err == nil) != tc.ok {
+				t.Fatalf("expected ok=%v, got %v", tc.ok, err)
+			}
+			if tc.ok && (id.Subject != "alice" || !id.Has(auth.CreateScope("http")) || id.Has(auth.ScopeTasksDelete)) {
+				t.Errorf("unexpected identity: %+v", id)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+signJWT(t, key, map[string]any{"sub": "alice", "aud": "task-runner"}))
+	if _, err := a.Authenticate(req); !errors.Is(err, auth.ErrTokenClaims) {
+		t.Errorf("expected ErrTokenClaims for a token without expiry, got %v", err)
+	}
+}
+
+// TestMiddleware checks that unauthenticated requests get a 401 problem, except on public paths.
+func TestMiddleware(t *testing.T) {
+	a, err := auth.New(auth.Config{APIKeys: []auth.APIKey{{Name: "ci", Hash: auth.HashKey("secret")}}})
+	if err != nil {
+		t.Fatalf("cannot create authenticator: %v", err)
+	}
+
+	var subject string
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := auth.FromContext(r.Context()); ok {
+			subject = id.Subject
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("expected 401 with WWW-Authenticate, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected public path to be served, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set(auth.APIKeyHeader, "secret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || subject != "ci" {
+		t.Errorf("expected request of ci to be served, got %d (%q)", rec.Code, subject)
+	}
+}
+
+// TestAuthenticate_ClientCert checks that verified client certificates are mapped by subject.
+func TestAuthenticate_ClientCert(t *testing.T) {
+	a, err := auth.New(auth.Config{ClientCerts: []auth.ClientCert{
+		{Subject: "ci", Name: "ci", Scopes: []string{auth.ScopeTasksRead}},
+		{Subject: "CN=ops,O=Example", Name: "ops", Scopes: []string{auth.ScopeAdmin}},
+	}})
+	if err != nil {
+		t.Fatalf("cannot create authenticator: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		subject pkix.Name
+		want    string
+	}{
+		{"common name", pkix.Name{CommonName: "ci", Organization: []string{"Other"}}, "ci"},
+		{"distinguished name", pkix.Name{CommonName: "ops", Organization: []string{"Example"}}, "ops"},
+		{"unknown", pkix.Name{CommonName: "ops", Organization: []string{"Other"}}, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: tc.subject}}}}
+
+			id, err := a.Authenticate(req)
+			if tc.want == "" {
+				if err == nil {
+					t.Fatalf("expected unknown certificate to be rejected, got %+v", id)
+				}
+				return
+			}
+			if err != nil || id.Subject != tc.want {
+				t.Fatalf("expected identity %s, got %+v (%v)", tc.want, id, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+)
+
+// ClientCert maps the subject of a TLS client certificate to an identity.
+// The certificate must have been verified against the client CA of the server.
+type ClientCert struct {
+	Subject string   // Common name (e.g. "ci") or full distinguished name (e.g. "CN=ci,O=Example")
+	Name    string   // Client name, used as the identity subject
+	Scopes  []string // Granted scopes
+}
+
+// matchClientCert returns the identity mapped to the subject of the certificate.
+func (a *Authenticator) matchClientCert(cert *x509.Certificate) (*Identity, error) {
+	dn := cert.Subject.String()
+	for _, c := range a.certs {
+		if c.Subject == dn || c.Subject == cert.Subject.CommonName {
+			return &Identity{Subject: c.Name, Scopes: c.Scopes}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown client certificate %q", dn)
+}
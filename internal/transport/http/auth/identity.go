@@ -0,0 +1,57 @@
+// Package auth authenticates API clients with static API keys or JWTs
+// and carries their identity and scopes in the request context.
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Scopes granted to API clients.
+const (
+	ScopeAdmin       = "admin"         // Every operation on every task, queue, and dead letter
+	ScopeTasksRead   = "tasks:read"    // Read own tasks, batches, and task types
+	ScopeTasksDelete = "tasks:delete"  // Delete and cancel own tasks
+	ScopeTasksCreate = "tasks:create:" // Prefix of the scopes creating tasks of a type ("tasks:create:*" for any type)
+)
+
+// CreateScope returns the scope needed to create tasks of the given type.
+func CreateScope(taskType string) string {
+	return ScopeTasksCreate + taskType
+}
+
+// Identity is an authenticated API client.
+type Identity struct {
+	Subject string   // Client name, recorded as the owner of its tasks
+	Scopes  []string // Granted scopes
+}
+
+// IsAdmin reports whether the client holds the admin scope.
+func (id *Identity) IsAdmin() bool {
+	return slices.Contains(id.Scopes, ScopeAdmin)
+}
+
+// Has reports whether the client holds the scope, directly, through "tasks:create:*", or as an admin.
+func (id *Identity) Has(scope string) bool {
+	if id.IsAdmin() || slices.Contains(id.Scopes, scope) {
+		return true
+	}
+
+	return strings.HasPrefix(scope, ScopeTasksCreate) && slices.Contains(id.Scopes, ScopeTasksCreate+"*")
+}
+
+// contextKey is the type of the context key holding the identity.
+type contextKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the identity.
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity carried by ctx, if any.
+// A request without identity was served without authentication.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(*Identity)
+	return id, ok
+}
@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// clockSkew is the tolerance applied to the time-based claims of a JWT.
+const clockSkew = 30 * time.Second
+
+// Errors returned when a JWT cannot be verified.
+var (
+	ErrTokenMalformed = errors.New("token malformed")
+	ErrTokenSignature = errors.New("token signature invalid")
+	ErrTokenExpired   = errors.New("token expired")
+	ErrTokenClaims    = errors.New("token claims invalid")
+)
+
+// jwk is a public key of a JWKS document.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey is a decoded public key with the JWT algorithm it verifies.
+type verificationKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// jwtHeader is the decoded header of a JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims holds the registered and scope claims used by the authenticator.
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Scope     string          `json:"scope"`  // Space-separated scopes (OAuth 2.0 style)
+	Scopes    []string        `json:"scopes"` // Scopes as an array
+}
+
+// loadJWKS reads the public keys of a JWKS file. RSA (RS256), P-256 (ES256),
+// and Ed25519 (EdDSA) keys are supported; other keys are skipped.
+func loadJWKS(path string) ([]verificationKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read JWKS file %q: %w", path, err)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("cannot decode JWKS file %q: %w", path, err)
+	}
+
+	var keys []verificationKey
+	for _, k := range doc.Keys {
+		key, err := k.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode key %q of JWKS file %q: %w", k.Kid, path, err)
+		}
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("cannot use JWKS file %q: no supported keys", path)
+	}
+
+	return keys, nil
+}
+
+// verificationKey decodes the key, or returns nil if its type is not supported.
+func (k jwk) verificationKey() (*verificationKey, error) {
+	switch {
+	case k.Kty == "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &verificationKey{kid: k.Kid, alg: "RS256", key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+
+	case k.Kty == "EC" && k.Crv == "P-256":
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the P-256 curve")
+		}
+		return &verificationKey{kid: k.Kid, alg: "ES256", key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, nil
+
+	case k.Kty == "OKP" && k.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return &verificationKey{kid: k.Kid, alg: "EdDSA", key: ed25519.PublicKey(x)}, nil
+	}
+
+	return nil, nil
+}
+
+// decodeBigInt decodes a base64url-encoded unsigned big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+// verifyJWT checks the signature and claims of a compact JWT and returns its claims.
+func (a *Authenticator) verifyJWT(token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	if !a.verifySignature(header, signed, signature) {
+		return nil, ErrTokenSignature
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := a.checkClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+// verifySignature reports whether one of the keys matching the header verifies the signature.
+func (a *Authenticator) verifySignature(header jwtHeader, signed, signature []byte) bool {
+	digest := sha256.Sum256(signed)
+
+	for _, k := range a.jwks {
+		if k.alg != header.Alg || (header.Kid != "" && k.kid != header.Kid) {
+			continue
+		}
+
+		switch key := k.key.(type) {
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		case *ecdsa.PublicKey:
+			if len(signature) == 64 {
+				r := new(big.Int).SetBytes(signature[:32])
+				s := new(big.Int).SetBytes(signature[32:])
+				if ecdsa.Verify(key, digest[:], r, s) {
+					return true
+				}
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, signed, signature) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkClaims validates the time-based claims and the configured issuer and audience.
+// Tokens without an expiry are rejected, so that a leaked token cannot be used forever.
+func (a *Authenticator) checkClaims(c *jwtClaims) error {
+	now := a.clock.Now()
+
+	if c.ExpiresAt == nil {
+		return fmt.Errorf("%w: missing expiry", ErrTokenClaims)
+	}
+	if now.After(unixTime(*c.ExpiresAt).Add(clockSkew)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != nil && now.Add(clockSkew).Before(unixTime(*c.NotBefore)) {
+		return fmt.Errorf("%w: not valid yet", ErrTokenClaims)
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("%w: missing subject", ErrTokenClaims)
+	}
+	if a.issuer != "" && c.Issuer != a.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrTokenClaims)
+	}
+	if a.audience != "" && !hasAudience(c.Audience, a.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrTokenClaims)
+	}
+
+	return nil
+}
+
+// identity returns the identity described by the claims.
+func (c *jwtClaims) identity() *Identity {
+	scopes := append(strings.Fields(c.Scope), c.Scopes...)
+	return &Identity{Subject: c.Subject, Scopes: scopes}
+}
+
+// hasAudience reports whether the "aud" claim, a string or an array of strings, contains the audience.
+func hasAudience(raw json.RawMessage, audience string) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == audience
+	}
+
+	var many []string
+	if json.Unmarshal(raw, &many) == nil {
+		for _, aud := range many {
+			if aud == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a JWT.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrTokenMalformed
+	}
+
+	return nil
+}
+
+// unixTime converts a NumericDate claim to a time.
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
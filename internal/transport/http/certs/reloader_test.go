@@ -0,0 +1,107 @@
+package certs_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/transport/http/certs"
+)
+
+// writeCert writes a self-signed certificate with the given serial number and its key as PEM files,
+// with the given modification time.
+func writeCert(t *testing.T, certFile, keyFile string, serial int64, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("cannot generate key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("cannot create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("cannot marshal key: %v", err)
+	}
+
+	writePEM(t, certFile, "CERTIFICATE", der, modTime)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER, modTime)
+}
+
+// writePEM writes a single PEM block to the file and sets its modification time.
+func writePEM(t *testing.T, path, blockType string, der []byte, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("cannot write %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("cannot set time of %s: %v", path, err)
+	}
+}
+
+// serial returns the serial number of the certificate currently served by the reloader.
+func serial(t *testing.T, r *certs.Reloader) int64 {
+	t.Helper()
+
+	cert, err := r.GetCertificate(nil)
+	if err != nil || cert == nil {
+		t.Fatalf("expected a certificate, got %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("cannot parse certificate: %v", err)
+	}
+
+	return leaf.SerialNumber.Int64()
+}
+
+// TestReloader checks that changed files are reloaded and that invalid ones keep the current certificate.
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	start := time.Now().Add(-time.Hour)
+	writeCert(t, certFile, keyFile, 1, start)
+
+	r, err := certs.NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewReloader failed: %v", err)
+	}
+	if reloaded, err := r.Reload(); reloaded || err != nil {
+		t.Fatalf("expected unchanged files not to be reloaded, got %v, %v", reloaded, err)
+	}
+
+	writeCert(t, certFile, keyFile, 2, start.Add(time.Minute))
+	if reloaded, err := r.Reload(); !reloaded || err != nil {
+		t.Fatalf("expected changed files to be reloaded, got %v, %v", reloaded, err)
+	}
+	if got := serial(t, r); got != 2 {
+		t.Errorf("expected certificate 2, got %d", got)
+	}
+
+	writePEM(t, keyFile, "EC PRIVATE KEY", []byte("garbage"), start.Add(2*time.Minute))
+	if _, err := r.Reload(); err == nil {
+		t.Fatal("expected invalid key to fail")
+	}
+	if got := serial(t, r); got != 2 {
+		t.Errorf("expected certificate 2 to be kept, got %d", got)
+	}
+}
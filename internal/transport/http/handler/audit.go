@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// ListAudit handles GET /audit and returns the audit entries, optionally of one "task_id", oldest first.
+// With "since" (RFC 3339) only the entries recorded from then on are returned,
+// and with "limit" only the most recent ones.
+func (h *TaskHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+	if h.AuditLog == nil {
+		response.RespondProblem(w, r, http.StatusNotFound, response.CodeAuditDisabled, response.ErrAuditDisabled)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := audit.Filter{TaskID: query.Get("task_id")}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			response.RespondProblem(w, r, http.StatusBadRequest, response.CodeInvalidQuery, response.ErrInvalidQuery)
+			return
+		}
+		filter.Limit = limit
+	}
+	if v := query.Get("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.RespondProblem(w, r, http.StatusBadRequest, response.CodeInvalidQuery, response.ErrInvalidQuery)
+			return
+		}
+		filter.Since = since
+	}
+
+	entries, err := h.AuditLog.Query(filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "cannot query audit log", slog.String("error", err.Error()))
+		response.RespondProblem(w, r, http.StatusInternalServerError, response.CodeInternal, response.ErrInternalServer)
+		return
+	}
+	if entries == nil {
+		entries = []*model.AuditEntry{}
+	}
+
+	response.RespondJSON(w, http.StatusOK, entries)
+}
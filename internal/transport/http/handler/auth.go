@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// requireScope reports whether the client holds the scope, sending a 403 problem response otherwise.
+// Requests served without authentication are always allowed.
+func requireScope(w http.ResponseWriter, r *http.Request, scope string) bool {
+	id, ok := auth.FromContext(r.Context())
+	if !ok || id.Has(scope) {
+		return true
+	}
+
+	response.RespondProblem(w, r, http.StatusForbidden, response.CodeForbidden, fmt.Sprintf("%s: %s", response.ErrForbidden, scope))
+	return false
+}
+
+// requestOwner returns the identity recorded as the owner of the tasks created by the request,
+// or an empty string without authentication.
+func requestOwner(r *http.Request) string {
+	if id, ok := auth.FromContext(r.Context()); ok {
+		return id.Subject
+	}
+
+	return ""
+}
+
+// visibleOwner returns the only owner whose tasks the client may see,
+// or an empty string for admins and requests served without authentication.
+func visibleOwner(r *http.Request) string {
+	if id, ok := auth.FromContext(r.Context()); ok && !id.IsAdmin() {
+		return id.Subject
+	}
+
+	return ""
+}
+
+// canAccess reports whether the client may access a task or batch of the given owner.
+func canAccess(r *http.Request, owner string) bool {
+	visible := visibleOwner(r)
+	return visible == "" || visible == owner
+}
+
+// findOwnTask returns the task with the given ID if the client may access it.
+// Otherwise it sends a problem response; tasks of other owners are reported as not found.
+func (h *TaskHandler) findOwnTask(w http.ResponseWriter, r *http.Request, id string) (*model.Task, bool) {
+	task, err := h.Manager.GetTask(id)
+	if err == nil && !canAccess(r, task.Owner) {
+		err = fmt.Errorf("cannot find task with ID %q: %w", id, service.ErrTaskNotFound)
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return nil, false
+	}
+
+	return task, true
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// createBatchRequest is the JSON body of POST /tasks:batch.
+type createBatchRequest struct {
+	Mode  model.BatchMode     `json:"mode"`  // Submission mode (atomic by default)
+	Tasks []createTaskRequest `json:"tasks"` // Tasks to create
+}
+
+// batchResponse is the JSON response of POST /tasks:batch.
+type batchResponse struct {
+	BatchID string              `json:"batch_id,omitempty"` // ID shared by the created tasks (empty if none was created)
+	Mode    model.BatchMode     `json:"mode"`               // Submission mode
+	Items   []batchItemResponse `json:"items"`              // One item per requested task, in request order
+}
+
+// batchItemResponse is the outcome of one task of a batch: either the created task or the error.
+type batchItemResponse struct {
+	Index int             `json:"index"`           // Position of the task in the request
+	Task  *model.Task     `json:"task,omitempty"`  // Created task
+	Error *batchItemError `json:"error,omitempty"` // Reason the task was not created
+}
+
+// batchItemError describes why a task of a best-effort batch was not created.
+type batchItemError struct {
+	Status int    `json:"status"` // HTTP status the error maps to
+	Code   string `json:"code"`   // Stable machine-readable error code
+	Detail string `json:"detail"` // Human-readable explanation
+}
+
+// CreateBatch handles POST /tasks:batch and creates several tasks sharing a batch ID.
+// Atomic batches are created as a whole or rejected with a problem response; best-effort batches
+// report the outcome of every task with 207 Multi-Status.
+func (h *TaskHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req createBatchRequest
+	if !decodeBody(w, r, &req, false) {
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = model.BatchModeAtomic
+	}
+
+	specs := make([]service.TaskSpec, len(req.Tasks))
+	for i, t := range req.Tasks {
+		if t.Type != "" && !requireScope(w, r, auth.CreateScope(t.Type)) {
+			return
+		}
+		specs[i] = service.TaskSpec{Type: t.Type, Params: t.Params}
+	}
+
+	id, items, err := h.Manager.CreateBatch(r.Context(), requestOwner(r), req.Mode, specs)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	resp := batchResponse{BatchID: id, Mode: req.Mode, Items: make([]batchItemResponse, len(items))}
+	for i, item := range items {
+		resp.Items[i] = batchItemResponse{Index: i, Task: item.Task}
+		if item.Err != nil {
+			status, code, detail := describeError(item.Err)
+			resp.Items[i].Error = &batchItemError{Status: status, Code: code, Detail: detail}
+		}
+	}
+
+	status := http.StatusCreated
+	if req.Mode == model.BatchModeBestEffort {
+		status = http.StatusMultiStatus
+	}
+
+	response.RespondJSON(w, status, resp)
+}
+
+// GetBatch handles GET /batches/{id} and returns the aggregate progress of a batch.
+func (h *TaskHandler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeTasksRead) {
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/batches/")
+	batch, err := h.Manager.GetBatch(id)
+	if err == nil && !canAccess(r, batch.Owner) {
+		err = fmt.Errorf("cannot find batch with ID %q: %w", id, service.ErrBatchNotFound)
+	}
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, batch)
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// decodeBody decodes the JSON request body into v and reports whether it succeeded,
+// sending a problem response otherwise. An empty body is accepted if it is optional.
+// Bodies cut off by the size limit of the server are reported as 413 Content Too Large.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any, optional bool) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil || optional && errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		detail := fmt.Sprintf("%s: limit is %d bytes", response.ErrRequestTooLarge, tooLarge.Limit)
+		response.RespondProblem(w, r, http.StatusRequestEntityTooLarge, response.CodeRequestTooLarge, detail)
+		return false
+	}
+
+	response.RespondProblem(w, r, http.StatusBadRequest, response.CodeInvalidRequestBody, response.ErrInvalidRequestBody)
+	return false
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// ListDeadLetters handles GET /dead-letters and returns the dead letters, optionally of one "type", oldest first.
+func (h *TaskHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, h.Manager.ListDeadLetters(r.URL.Query().Get("type")))
+}
+
+// GetDeadLetter handles GET /dead-letters/{id} and returns a failed task with its error history.
+func (h *TaskHandler) GetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/dead-letters/")
+	letter, err := h.Manager.GetDeadLetter(id)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, letter)
+}
+
+// RequeueDeadLetter handles POST /dead-letters/{id}/requeue and queues the failed task again in place.
+func (h *TaskHandler) RequeueDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/dead-letters/"), "/requeue")
+	task, err := h.Manager.RequeueDeadLetter(r.Context(), id)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, task)
+}
+
+// DiscardDeadLetter handles DELETE /dead-letters/{id} and removes a dead letter for good.
+func (h *TaskHandler) DiscardDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/dead-letters/")
+	err := h.Manager.DiscardDeadLetter(r.Context(), id)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondNoContent(w, http.StatusNoContent)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// serviceError maps a service sentinel error to its HTTP status and problem code.
+type serviceError struct {
+	err    error
+	status int
+	code   string
+}
+
+// serviceErrors lists the service errors exposed to clients; anything else is an internal error.
+var serviceErrors = []serviceError{
+	{service.ErrTaskUnknownType, http.StatusBadRequest, response.CodeUnknownTaskType},
+	{service.ErrTaskInvalidParams, http.StatusBadRequest, response.CodeInvalidParams},
+	{service.ErrTaskTypeDisabled, http.StatusServiceUnavailable, response.CodeTaskTypeDisabled},
+	{service.ErrTaskAlreadyExists, http.StatusConflict, response.CodeTaskAlreadyExists},
+	{service.ErrTaskQueueLimitReached, http.StatusTooManyRequests, response.CodeQueueLimitReached},
+	{service.ErrTaskNotFound, http.StatusNotFound, response.CodeTaskNotFound},
+	{service.ErrTaskInProgress, http.StatusConflict, response.CodeTaskInProgress},
+	{service.ErrTaskFinished, http.StatusConflict, response.CodeTaskFinished},
+	{service.ErrTaskManagerClosed, http.StatusServiceUnavailable, response.CodeShuttingDown},
+	{service.ErrTaskInvalidBatch, http.StatusBadRequest, response.CodeInvalidBatch},
+	{service.ErrBatchNotFound, http.StatusNotFound, response.CodeBatchNotFound},
+	{service.ErrTaskTypePaused, http.StatusServiceUnavailable, response.CodeQueuePaused},
+	{service.ErrTaskNotFinished, http.StatusConflict, response.CodeTaskNotFinished},
+	{service.ErrDeadLetterNotFound, http.StatusNotFound, response.CodeDeadLetterNotFound},
+	{service.ErrClientRateLimited, http.StatusTooManyRequests, response.CodeRateLimited},
+	{service.ErrClientQuotaReached, http.StatusTooManyRequests, response.CodeQuotaExceeded},
+	{service.ErrClientBurstExceeded, http.StatusBadRequest, response.CodeBurstExceeded},
+	{service.ErrAuditFailed, http.StatusServiceUnavailable, response.CodeAuditFailed},
+}
+
+// respondError sends a problem response for an error returned by the task manager.
+// Unknown errors are reported as internal errors without exposing their message.
+// Exceeded client limits are also reported in the Retry-After and X-RateLimit-* headers.
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	var limitErr *service.ClientLimitError
+	if errors.As(err, &limitErr) {
+		setLimitHeaders(w.Header(), limitErr)
+	}
+
+	status, code, detail := describeError(err)
+	response.RespondProblem(w, r, status, code, detail)
+}
+
+// setLimitHeaders describes the exceeded client limit in the response headers.
+func setLimitHeaders(h http.Header, err *service.ClientLimitError) {
+	if err.RetryAfter > 0 {
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	}
+	if err.RetryAfter > 0 || errors.Is(err, service.ErrClientBurstExceeded) {
+		h.Set("X-RateLimit-Limit", strconv.FormatFloat(err.Limits.Rate, 'g', -1, 64))
+		h.Set("X-RateLimit-Burst", strconv.Itoa(err.Limits.Burst))
+		return
+	}
+
+	h.Set("X-Quota-Limit", strconv.Itoa(err.Limits.MaxPending))
+}
+
+// describeError returns the HTTP status, problem code, and detail of an error returned by the task manager.
+func describeError(err error) (int, string, string) {
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.err) {
+			return e.status, e.code, err.Error()
+		}
+	}
+
+	return http.StatusInternalServerError, response.CodeInternal, response.ErrInternalServer
+}
@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/kylerqws/task-runner/internal/transport/http/openapi"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// OpenAPI handles GET /openapi.json and returns the OpenAPI document of the API,
+// including the parameter schemas of the currently registered task types.
+func (h *TaskHandler) OpenAPI(w http.ResponseWriter, _ *http.Request) {
+	response.RespondJSON(w, http.StatusOK, openapi.Spec(h.Manager.ListTaskTypes()))
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// purgeQueueResponse is the JSON response of POST /queues/{type}:purge.
+type purgeQueueResponse struct {
+	Type    string   `json:"type"`     // Task type of the purged queue
+	Count   int      `json:"count"`    // Number of purged tasks
+	TaskIDs []string `json:"task_ids"` // IDs of the purged tasks, in queue order
+}
+
+// Purge handles POST /queues/{type}:purge and removes all pending tasks of the type.
+func (h *TaskHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	taskType := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/queues/"), ":purge")
+	ids, err := h.Manager.PurgeQueue(r.Context(), taskType)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, purgeQueueResponse{Type: taskType, Count: len(ids), TaskIDs: ids})
+}
+
+// pauseQueueRequest is the optional JSON body of POST /queues/{type}/pause.
+type pauseQueueRequest struct {
+	RejectNew bool `json:"reject_new"` // Reject new tasks while paused instead of queueing them
+}
+
+// ListQueues handles GET /queues and returns the queues of all registered task types.
+func (h *TaskHandler) ListQueues(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, h.Manager.ListQueues())
+}
+
+// GetQueue handles GET /queues/{type} and returns the state and load of a queue.
+func (h *TaskHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	taskType := strings.TrimPrefix(r.URL.Path, "/queues/")
+	queue, err := h.Manager.GetQueue(taskType)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, queue)
+}
+
+// Pause handles POST /queues/{type}/pause and stops the queue from starting tasks.
+func (h *TaskHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	var req pauseQueueRequest
+	if !decodeBody(w, r, &req, true) {
+		return
+	}
+
+	taskType := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/queues/"), "/pause")
+	queue, err := h.Manager.PauseQueue(r.Context(), taskType, req.RejectNew)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, queue)
+}
+
+// Resume handles POST /queues/{type}/resume and lets the queue start tasks again.
+func (h *TaskHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	taskType := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/queues/"), "/resume")
+	queue, err := h.Manager.ResumeQueue(r.Context(), taskType)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, queue)
+}
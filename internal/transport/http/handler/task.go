@@ -1,17 +1,23 @@
 package handler
 
 import (
-	"errors"
+	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/domain/model"
 	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
 	"github.com/kylerqws/task-runner/internal/transport/http/response"
 )
 
 // TaskHandler handles HTTP requests for task management operations.
 type TaskHandler struct {
-	Manager *service.TaskManager
+	Manager  *service.TaskManager
+	AuditLog *audit.Log // Audit log served by GET /audit (nil if disabled)
 }
 
 // NewTaskHandler creates a new TaskHandler with the provided TaskManager.
@@ -19,40 +25,123 @@ func NewTaskHandler(manager *service.TaskManager) *TaskHandler {
 	return &TaskHandler{Manager: manager}
 }
 
-// Create handles POST /tasks and creates a new task based on the given type.
+// createTaskRequest is the optional JSON body of POST /tasks.
+type createTaskRequest struct {
+	Type   string          `json:"type"`   // Task type (the "type" query parameter takes precedence)
+	Params json.RawMessage `json:"params"` // Type-specific task parameters
+}
+
+// Create handles POST /tasks and creates a new task based on the given type and parameters.
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
-	taskType := r.URL.Query().Get("type")
-	task, err := h.Manager.CreateTask(taskType)
+	var req createTaskRequest
+	if !decodeBody(w, r, &req, true) {
+		return
+	}
+	if taskType := r.URL.Query().Get("type"); taskType != "" {
+		req.Type = taskType
+	}
+	if req.Type != "" && !requireScope(w, r, auth.CreateScope(req.Type)) {
+		return
+	}
+
+	task, err := h.Manager.CreateTaskFor(r.Context(), requestOwner(r), req.Type, req.Params)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrTaskUnknownType):
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		case errors.Is(err, service.ErrTaskAlreadyExists):
-			http.Error(w, err.Error(), http.StatusConflict)
-		case errors.Is(err, service.ErrTaskQueueLimitReached):
-			http.Error(w, err.Error(), http.StatusTooManyRequests)
-		default:
-			http.Error(w, response.ErrInternalServer, http.StatusInternalServerError)
-		}
+		respondError(w, r, err)
 		return
 	}
 
 	response.RespondJSON(w, http.StatusCreated, task)
 }
 
-// Get handles GET /tasks/{id} and returns task details.
-func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
-	id := strings.TrimPrefix(r.URL.Path, "/tasks/")
-	task, err := h.Manager.GetTask(id)
+// deleteTasksResponse is the JSON response of DELETE /tasks.
+type deleteTasksResponse struct {
+	DryRun  bool     `json:"dry_run"`  // Nothing was deleted
+	Count   int      `json:"count"`    // Number of affected tasks
+	TaskIDs []string `json:"task_ids"` // IDs of the affected tasks, oldest first
+}
+
+// List handles GET /tasks and returns the tasks matching the optional "status", "type", and "older_than" filters.
+// Clients without the admin scope only see their own tasks.
+func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeTasksRead) {
+		return
+	}
+
+	filter, ok := h.parseFilter(r)
+	if !ok {
+		response.RespondProblem(w, r, http.StatusBadRequest, response.CodeInvalidQuery, response.ErrInvalidQuery)
+		return
+	}
+	filter.Owner = visibleOwner(r)
+
+	response.RespondJSON(w, http.StatusOK, h.Manager.ListTasks(filter))
+}
+
+// DeleteMany handles DELETE /tasks and removes the tasks matching the "status", "type", and "older_than" filters.
+// At least one filter is required; with "dry_run=true" the affected tasks are only reported.
+func (h *TaskHandler) DeleteMany(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeAdmin) {
+		return
+	}
+
+	filter, ok := h.parseFilter(r)
+	if !ok || filter == (service.TaskFilter{}) {
+		response.RespondProblem(w, r, http.StatusBadRequest, response.CodeInvalidQuery, response.ErrInvalidQuery)
+		return
+	}
+
+	var dryRun bool
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			response.RespondProblem(w, r, http.StatusBadRequest, response.CodeInvalidQuery, response.ErrInvalidQuery)
+			return
+		}
+	}
+
+	ids, err := h.Manager.DeleteTasks(r.Context(), filter, dryRun)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrTaskNotFound):
-			http.Error(w, err.Error(), http.StatusNotFound)
-		default:
-			http.Error(w, response.ErrInternalServer, http.StatusInternalServerError)
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, deleteTasksResponse{DryRun: dryRun, Count: len(ids), TaskIDs: ids})
+}
+
+// parseFilter reads the task filter from the query parameters and reports whether they are valid.
+// "older_than" is a positive duration (e.g. "1h") relative to the current time.
+func (h *TaskHandler) parseFilter(r *http.Request) (service.TaskFilter, bool) {
+	query := r.URL.Query()
+	filter := service.TaskFilter{
+		Status: model.TaskStatus(query.Get("status")),
+		Type:   query.Get("type"),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return filter, false
+	}
+
+	if v := query.Get("older_than"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return filter, false
 		}
+		filter.CreatedBefore = h.Manager.Clock().Now().Add(-d)
+	}
+
+	return filter, true
+}
+
+// Get handles GET /tasks/{id} and returns task details.
+func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeTasksRead) {
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/tasks/")
+	task, ok := h.findOwnTask(w, r, id)
+	if !ok {
 		return
 	}
 
@@ -61,20 +150,74 @@ func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 // Delete handles DELETE /tasks/{id} and removes a task if it's not running.
 func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeTasksDelete) {
+		return
+	}
+
 	id := strings.TrimPrefix(r.URL.Path, "/tasks/")
-	err := h.Manager.DeleteTask(id)
+	if _, ok := h.findOwnTask(w, r, id); !ok {
+		return
+	}
+	err := h.Manager.DeleteTask(r.Context(), id)
 
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrTaskNotFound):
-			http.Error(w, err.Error(), http.StatusNotFound)
-		case errors.Is(err, service.ErrTaskInProgress):
-			http.Error(w, err.Error(), http.StatusConflict)
-		default:
-			http.Error(w, response.ErrInternalServer, http.StatusInternalServerError)
-		}
+		respondError(w, r, err)
 		return
 	}
 
 	response.RespondNoContent(w, http.StatusNoContent)
 }
+
+// Cancel handles POST /tasks/{id}/cancel and cancels a pending or running task.
+func (h *TaskHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeTasksDelete) {
+		return
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/tasks/"), "/cancel")
+	if _, ok := h.findOwnTask(w, r, id); !ok {
+		return
+	}
+	err := h.Manager.CancelTask(r.Context(), id)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	response.RespondNoContent(w, http.StatusAccepted)
+}
+
+// retryTaskRequest is the optional JSON body of POST /tasks/{id}/retry.
+type retryTaskRequest struct {
+	InPlace bool `json:"in_place"` // Re-queue the same task instead of creating a new one
+}
+
+// Retry handles POST /tasks/{id}/retry and queues a finished task again,
+// either as a new task linked by "retry_of" or in place with the same ID.
+func (h *TaskHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	var req retryTaskRequest
+	if !decodeBody(w, r, &req, true) {
+		return
+	}
+
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/tasks/"), "/retry")
+	task, ok := h.findOwnTask(w, r, id)
+	if !ok || !requireScope(w, r, auth.CreateScope(task.Type)) {
+		return
+	}
+
+	task, err := h.Manager.RetryTask(r.Context(), id, req.InPlace)
+
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if req.InPlace {
+		status = http.StatusOK
+	}
+
+	response.RespondJSON(w, status, task)
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// ListTypes handles GET /task-types and returns all registered task types.
+func (h *TaskHandler) ListTypes(w http.ResponseWriter, r *http.Request) {
+	if !requireScope(w, r, auth.ScopeTasksRead) {
+		return
+	}
+
+	response.RespondJSON(w, http.StatusOK, h.Manager.ListTaskTypes())
+}
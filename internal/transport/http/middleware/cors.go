@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig holds the cross-origin requests allowed by CORS.
+type CORSConfig struct {
+	AllowedOrigins   []string      // Origins allowed to call the API ("*" allows any)
+	AllowedMethods   []string      // Methods allowed in preflight requests (defaults to DefaultCORSMethods)
+	AllowedHeaders   []string      // Request headers allowed in preflight requests (defaults to DefaultCORSHeaders)
+	ExposedHeaders   []string      // Response headers readable by the browser (defaults to DefaultCORSExposedHeaders)
+	AllowCredentials bool          // Allow cookies and credentials (the origin is echoed instead of "*")
+	MaxAge           time.Duration // How long browsers may cache a preflight response (0 leaves it to the browser)
+}
+
+// Defaults of CORSConfig.
+var (
+	DefaultCORSMethods        = []string{http.MethodGet, http.MethodPost, http.MethodDelete}
+	DefaultCORSHeaders        = []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "traceparent"}
+	DefaultCORSExposedHeaders = []string{"X-Request-ID", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Burst", "X-Quota-Limit"}
+)
+
+// CORS answers preflight requests and adds the CORS headers to responses to allowed origins.
+// Requests from other origins are served without CORS headers, so browsers block them.
+// Preflight requests are answered before the wrapped handler, so they need no credentials.
+func CORS(cfg CORSConfig) Middleware {
+	methods := strings.Join(orDefault(cfg.AllowedMethods, DefaultCORSMethods), ", ")
+	headers := strings.Join(orDefault(cfg.AllowedHeaders, DefaultCORSHeaders), ", ")
+	exposed := strings.Join(orDefault(cfg.ExposedHeaders, DefaultCORSExposedHeaders), ", ")
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if !anyOrigin && !slices.Contains(cfg.AllowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin && !cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+				h.Set("Access-Control-Allow-Methods", methods)
+				h.Set("Access-Control-Allow-Headers", headers)
+				if cfg.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			h.Set("Access-Control-Expose-Headers", exposed)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// orDefault returns values, or defaults if values is empty.
+func orDefault(values, defaults []string) []string {
+	if len(values) == 0 {
+		return defaults
+	}
+
+	return values
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// gzipWriters pools the gzip writers, which are expensive to allocate.
+var gzipWriters = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// Gzip compresses responses for clients that accept gzip.
+// Responses without a body (HEAD, 204, 304) and responses already encoded by the handler are sent as they are.
+func Gzip() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w}
+			defer gw.close()
+
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// gzipResponseWriter compresses the body written to the wrapped writer.
+// Whether to compress is decided when the header is written.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	decided bool         // Header written and compression decided
+	zw      *gzip.Writer // Compressor of the body (nil if the body is sent as it is)
+}
+
+// WriteHeader decides whether to compress the response and sends the header.
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	h := w.Header()
+	if status != http.StatusNoContent && status != http.StatusNotModified && h.Get("Content-Encoding") == "" {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+
+		w.zw = gzipWriters.Get().(*gzip.Writer)
+		w.zw.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses the bytes, sending a 200 header first if none was sent.
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.zw == nil {
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.zw.Write(b)
+}
+
+// Flush sends the bytes compressed so far to the client.
+func (w *gzipResponseWriter) Flush() {
+	if w.zw != nil {
+		_ = w.zw.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close finishes the compressed stream and returns the compressor to the pool.
+func (w *gzipResponseWriter) close() {
+	if w.zw == nil {
+		return
+	}
+
+	_ = w.zw.Close()
+	w.zw.Reset(nil)
+	gzipWriters.Put(w.zw)
+	w.zw = nil
+}
+
+// acceptsGzip reports whether the client accepts gzip-encoded responses:
+// gzip is listed with a quality value above zero, or without one.
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			return quality(params) > 0
+		}
+	}
+
+	return false
+}
+
+// quality returns the "q" parameter of an Accept-Encoding entry, 1 if it is not set,
+// or 0 if it is not a valid number between 0 and 1.
+func quality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 0
+		}
+		return q
+	}
+
+	return 1
+}
@@ -0,0 +1,63 @@
+// Package middleware provides composable HTTP middleware: request IDs, access logs, tracing,
+// panic recovery, CORS, and gzip compression.
+package middleware
+
+import (
+	"net/http"
+)
+
+// Middleware wraps an HTTP handler with additional behavior.
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps the handler with the middleware, the first one being the outermost.
+// Nil middleware is skipped, so that optional pieces can be passed as they are.
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			h = mws[i](h)
+		}
+	}
+
+	return h
+}
+
+// responseRecorder records the status and size of a response passed to the wrapped writer.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status int   // Status code sent (0 until the header is written)
+	bytes  int64 // Body bytes written
+}
+
+// WriteHeader records the status code and sends the header.
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the written bytes, sending a 200 header first if none was sent.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+
+	return n, err
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// recordResponse returns w as a *responseRecorder, wrapping it unless it already is one.
+func recordResponse(w http.ResponseWriter) *responseRecorder {
+	if rec, ok := w.(*responseRecorder); ok {
+		return rec
+	}
+
+	return &responseRecorder{ResponseWriter: w}
+}
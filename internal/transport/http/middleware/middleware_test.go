@@ -0,0 +1,205 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kylerqws/task-runner/internal/logging"
+	"github.com/kylerqws/task-runner/internal/tracing"
+	"github.com/kylerqws/task-runner/internal/transport/http/middleware"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// hello responds with a fixed JSON body.
+var hello = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"hello":"world"}`))
+})
+
+// TestRequestID checks that client IDs are kept when valid, replaced otherwise, and passed to the handler.
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := middleware.Chain(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+		if got := response.RequestID(r); got != seen {
+			t.Errorf("expected header ID %q to match the context, got %q", seen, got)
+		}
+	}), middleware.RequestID())
+
+	for id, keep := range map[string]bool{"abc-123": true, "": false, "bad\nid": false, strings.Repeat("x", 200): false} {
+		r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		r.Header.Set(response.RequestIDHeader, id)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		got := w.Header().Get(response.RequestIDHeader)
+		if got == "" || got != seen {
+			t.Errorf("expected response ID %q to match the handler's %q", got, seen)
+		}
+		if (got == id) != keep {
+			t.Errorf("ID %q: expected kept=%v, got %q", id, keep, got)
+		}
+	}
+}
+
+// TestAccessLog checks the logged fields of a request.
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	h := middleware.Chain(hello, middleware.RequestID(), middleware.AccessLog(logger))
+
+	r := httptest.NewRequest(http.MethodGet, "/tasks?status=done", nil)
+	r.Header.Set(response.RequestIDHeader, "req-1")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	var entry struct {
+		Msg       string `json:"msg"`
+		Method    string `json:"method"`
+		Path      string `json:"path"`
+		Status    int    `json:"status"`
+		Bytes     int    `json:"bytes"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("cannot decode log entry %q: %v", buf.String(), err)
+	}
+	if entry.Method != http.MethodGet || entry.Path != "/tasks" || entry.Status != http.StatusOK ||
+		entry.Bytes != len(`{"hello":"world"}`) || entry.RequestID != "req-1" {
+		t.Errorf("unexpected log entry %+v", entry)
+	}
+}
+
+// TestRecover checks that a panic becomes a logged 500 problem response.
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	h := middleware.Chain(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	}), middleware.RequestID(), middleware.Recover(logger))
+
+	r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	r.Header.Set(response.RequestIDHeader, "req-2")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	var problem response.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("cannot decode problem: %v", err)
+	}
+	if w.Code != http.StatusInternalServerError || problem.Code != response.CodeInternal || problem.RequestID != "req-2" {
+		t.Errorf("unexpected response %d %+v", w.Code, problem)
+	}
+	if !strings.Contains(buf.String(), "boom") {
+		t.Errorf("expected the panic to be logged, got %q", buf.String())
+	}
+}
+
+// TestCORS checks preflight responses and the headers of allowed and other origins.
+func TestCORS(t *testing.T) {
+	h := middleware.Chain(hello, middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		MaxAge:         10 * time.Minute,
+	}))
+
+	r := httptest.NewRequest(http.MethodOptions, "/tasks", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		!strings.Contains(w.Header().Get("Access-Control-Allow-Methods"), http.MethodPost) ||
+		w.Header().Get("Access-Control-Max-Age") != "600" {
+		t.Errorf("unexpected preflight response %d %v", w.Code, w.Header())
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	r.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected no CORS headers for another origin, got %d %v", w.Code, w.Header())
+	}
+}
+
+// TestGzip checks that responses are compressed only for clients that accept gzip.
+func TestGzip(t *testing.T) {
+	h := middleware.Chain(hello, middleware.Gzip())
+
+	r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	r.Header.Set("Accept-Encoding", "br, gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzip response, got %v", w.Header())
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("cannot read gzip body: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil || string(body) != `{"hello":"world"}` {
+		t.Errorf("unexpected body %q, %v", body, err)
+	}
+
+	cases := map[string]bool{
+		"gzip;q=0":           false,
+		"gzip;q=0.0":         false,
+		"gzip;q=0.000":       false,
+		"br, gzip; q=0":      false,
+		"gzip;q=abc":         false,
+		"identity":           false,
+		"gzip;q=0.5":         true,
+		"gzip; level=1; Q=1": true,
+	}
+	for accept, compressed := range cases {
+		r = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		r.Header.Set("Accept-Encoding", accept)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got := w.Header().Get("Content-Encoding") == "gzip"; got != compressed {
+			t.Errorf("Accept-Encoding %q: expected compressed=%v, got %v", accept, compressed, w.Header())
+		}
+		if !compressed && w.Body.String() != `{"hello":"world"}` {
+			t.Errorf("Accept-Encoding %q: expected a plain response, got %q", accept, w.Body.String())
+		}
+	}
+}
+
+// TestTrace checks that the request span continues the trace of the client and is passed to the handler.
+func TestTrace(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	var seen tracing.SpanContext
+	h := middleware.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = tracing.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusBadGateway)
+	}), middleware.Trace(tracing.NewTracer(exporter)))
+
+	r := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+	r.Header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "HTTP POST" || span.Kind != tracing.SpanKindServer || span.Status != tracing.StatusError ||
+		span.SpanContext.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent.SpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("unexpected span %+v", span)
+	}
+	if seen != span.SpanContext {
+		t.Errorf("expected the handler to see span %+v, got %+v", span.SpanContext, seen)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/kylerqws/task-runner/internal/logging"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// Recover turns a panic in a handler into a 500 "internal_error" problem response and logs it
+// with the stack trace, instead of dropping the connection. If the response was already started,
+// it can only be cut short. http.ErrAbortHandler is passed on, as it is meant to abort the response.
+func Recover(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := recordResponse(w)
+
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+
+				logger.LogAttrs(r.Context(), slog.LevelError, "http handler panic",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("request_id", logging.RequestID(r.Context())),
+					slog.String("panic", fmt.Sprint(v)),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				if rec.status == 0 {
+					response.RespondProblem(rec, r, http.StatusInternalServerError, response.CodeInternal, response.ErrInternalServer)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/kylerqws/task-runner/internal/logging"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+)
+
+// maxRequestIDLength is the max length of a request ID accepted from a client.
+const maxRequestIDLength = 128
+
+// RequestID makes sure every request has an ID: the one sent by the client in the X-Request-ID header,
+// or a new random one if it is missing or malformed. The ID is set on the request header, so that error
+// responses report it, stored in the request context (see logging.RequestID), and echoed in the response header.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(response.RequestIDHeader)
+			if !validRequestID(id) {
+				r.Header.Del(response.RequestIDHeader)
+				id = response.RequestID(r)
+				r.Header.Set(response.RequestIDHeader, id)
+			}
+
+			w.Header().Set(response.RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+		})
+	}
+}
+
+// validRequestID reports whether a client-provided ID is short and made of printable ASCII only,
+// so that it is safe to log and echo.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kylerqws/task-runner/internal/logging"
+	"github.com/kylerqws/task-runner/internal/tracing"
+)
+
+// Trace records a server span for every request. A valid W3C traceparent header makes the span
+// continue the trace of the client; otherwise a new trace is started. The span is stored in the
+// request context, so that tasks queued by the request continue its trace.
+func Trace(tracer *tracing.Tracer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if parent, err := tracing.ParseTraceparent(r.Header.Get(tracing.TraceparentHeader)); err == nil {
+				ctx = tracing.ContextWithRemoteSpanContext(ctx, parent)
+			}
+
+			ctx, span := tracer.Start(ctx, "HTTP "+r.Method,
+				tracing.WithKind(tracing.SpanKindServer),
+				tracing.WithAttributes(
+					slog.String("http.request.method", r.Method),
+					slog.String("url.path", r.URL.Path),
+					slog.String("user_agent.original", r.UserAgent()),
+				),
+			)
+			if id := logging.RequestID(ctx); id != "" {
+				span.SetAttributes(slog.String("request_id", id))
+			}
+
+			rec := recordResponse(w)
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(slog.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(tracing.StatusError, http.StatusText(status))
+			}
+			span.End()
+		})
+	}
+}
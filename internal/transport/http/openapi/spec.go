@@ -0,0 +1,567 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+)
+
+// Version is the OpenAPI version of the generated document.
+const Version = "3.1.0"
+
+// object is a JSON object of the OpenAPI document.
+type object = map[string]any
+
+// Spec returns the OpenAPI document of the HTTP API.
+// The parameters of the create request are described by the schemas of the given task types.
+func Spec(types []*model.TaskType) object {
+	return object{
+		"openapi": Version,
+		"info": object{
+			"title":       "Task Runner API",
+			"version":     "1.0.0",
+			"description": "Queue and run asynchronous tasks of registered types.",
+		},
+		// Credentials are only required when authentication is enabled on the server.
+		"security": []any{object{"ApiKey": []any{}}, object{"BearerAuth": []any{}}},
+		"paths": object{
+			"/tasks": object{
+				"get": operation("listTasks", "List tasks, oldest first", object{
+					"parameters": filterParameters(),
+				}, http.StatusOK, object{"type": "array", "items": ref("Task")}, http.StatusBadRequest),
+				"delete": operation("deleteTasks", "Delete the tasks matching the filters, except running ones", object{
+					"description": "At least one filter is required.",
+					"parameters": append(filterParameters(),
+						queryParameter("dry_run", "Only report the tasks that would be deleted.", object{"type": "boolean"})),
+				}, http.StatusOK, ref("DeleteTasksResult"), http.StatusBadRequest),
+				"post": operation("createTask", "Create a task", object{
+					"parameters": []any{
+						queryParameter("type", "Task type; takes precedence over the type in the body.", object{"type": "string"}),
+					},
+					"requestBody": object{
+						"required": false,
+						"content":  jsonContent(ref("CreateTaskRequest")),
+					},
+				}, http.StatusCreated, ref("Task"),
+					http.StatusBadRequest, http.StatusConflict, http.StatusTooManyRequests, http.StatusServiceUnavailable),
+			},
+			"/tasks/{id}": object{
+				"parameters": []any{idParameter()},
+				"get": operation("getTask", "Get a task by ID", nil,
+					http.StatusOK, ref("Task"), http.StatusNotFound),
+				"delete": operation("deleteTask", "Delete a task that is not running", nil,
+					http.StatusNoContent, nil, http.StatusNotFound, http.StatusConflict),
+			},
+			"/tasks/{id}/cancel": object{
+				"parameters": []any{idParameter()},
+				"post": operation("cancelTask", "Cancel a pending or running task", nil,
+					http.StatusAccepted, nil, http.StatusNotFound, http.StatusConflict),
+			},
+			"/tasks/{id}/retry": object{
+				"parameters": []any{idParameter()},
+				"post": operation("retryTask", "Queue a finished task again", object{
+					"description": "By default a new task linked by retry_of is created (201). " +
+						"With in_place the same task is re-queued and its last run is appended to attempts (200).",
+					"requestBody": object{
+						"required": false,
+						"content": jsonContent(object{
+							"type": "object",
+							"properties": object{
+								"in_place": object{"type": "boolean", "description": "Re-queue the same task."},
+							},
+						}),
+					},
+					"responses": object{
+						"200":     object{"description": "Task re-queued in place", "content": jsonContent(ref("Task"))},
+						"201":     object{"description": "Retry task created", "content": jsonContent(ref("Task"))},
+						"404":     object{"$ref": "#/components/responses/Problem"},
+						"409":     object{"$ref": "#/components/responses/Problem"},
+						"429":     object{"$ref": "#/components/responses/Problem"},
+						"503":     object{"$ref": "#/components/responses/Problem"},
+						"default": object{"$ref": "#/components/responses/Problem"},
+					},
+				}, http.StatusCreated, nil),
+			},
+			"/tasks:batch": object{
+				"post": operation("createBatch", "Create several tasks sharing a batch ID", object{
+					"description": "Atomic batches are created as a whole or rejected; " +
+						"best-effort batches respond with 207 and the outcome of every task.",
+					"requestBody": object{
+						"required": true,
+						"content":  jsonContent(ref("CreateBatchRequest")),
+					},
+					"responses": object{
+						"201":     object{"description": "Atomic batch created", "content": jsonContent(ref("BatchResult"))},
+						"207":     object{"description": "Best-effort batch processed", "content": jsonContent(ref("BatchResult"))},
+						"400":     object{"$ref": "#/components/responses/Problem"},
+						"429":     object{"$ref": "#/components/responses/Problem"},
+						"503":     object{"$ref": "#/components/responses/Problem"},
+						"default": object{"$ref": "#/components/responses/Problem"},
+					},
+				}, http.StatusCreated, nil),
+			},
+			"/batches/{id}": object{
+				"parameters": []any{idParameter()},
+				"get": operation("getBatch", "Get the aggregate progress of a batch", nil,
+					http.StatusOK, ref("Batch"), http.StatusNotFound),
+			},
+			"/queues": object{
+				"get": operation("listQueues", "List the queues of all task types", nil,
+					http.StatusOK, object{"type": "array", "items": ref("Queue")}),
+			},
+			"/queues/{type}": object{
+				"parameters": []any{typeParameter()},
+				"get": operation("getQueue", "Get the state and load of a queue", nil,
+					http.StatusOK, ref("Queue"), http.StatusBadRequest),
+			},
+			"/queues/{type}/pause": object{
+				"parameters": []any{typeParameter()},
+				"post": operation("pauseQueue", "Stop a queue from starting tasks", object{
+					"requestBody": object{
+						"required": false,
+						"content": jsonContent(object{
+							"type": "object",
+							"properties": object{
+								"reject_new": object{"type": "boolean", "description": "Reject new tasks while paused."},
+							},
+						}),
+					},
+				}, http.StatusOK, ref("Queue"), http.StatusBadRequest, http.StatusServiceUnavailable),
+			},
+			"/queues/{type}/resume": object{
+				"parameters": []any{typeParameter()},
+				"post": operation("resumeQueue", "Let a paused queue start tasks again", nil,
+					http.StatusOK, ref("Queue"), http.StatusBadRequest),
+			},
+			"/queues/{type}:purge": object{
+				"parameters": []any{typeParameter()},
+				"post": operation("purgeQueue", "Delete all pending tasks of a type", nil,
+					http.StatusOK, ref("PurgeQueueResult"), http.StatusBadRequest),
+			},
+			"/dead-letters": object{
+				"get": operation("listDeadLetters", "List failed tasks moved to the dead letters, oldest first", object{
+					"parameters": []any{queryParameter("type", "Only dead letters of this type.", object{"type": "string"})},
+				}, http.StatusOK, object{"type": "array", "items": ref("DeadLetter")}),
+			},
+			"/dead-letters/{id}": object{
+				"parameters": []any{idParameter()},
+				"get": operation("getDeadLetter", "Get a dead letter with its error history", nil,
+					http.StatusOK, ref("DeadLetter"), http.StatusNotFound),
+				"delete": operation("discardDeadLetter", "Discard a dead letter", nil,
+					http.StatusNoContent, nil, http.StatusNotFound),
+			},
+			"/dead-letters/{id}/requeue": object{
+				"parameters": []any{idParameter()},
+				"post": operation("requeueDeadLetter", "Queue a dead letter again with the same ID", nil,
+					http.StatusOK, ref("Task"), http.StatusBadRequest, http.StatusNotFound, http.StatusTooManyRequests,
+					http.StatusServiceUnavailable),
+			},
+			"/audit": object{
+				"get": operation("listAuditEntries", "List the audit entries of state-changing operations, oldest first", object{
+					"parameters": []any{
+						queryParameter("task_id", "Only entries of this task and of the retries created from it.", object{"type": "string"}),
+						queryParameter("since", "Only entries recorded at or after this time.", object{"type": "string", "format": "date-time"}),
+						queryParameter("limit", "Max entries returned, the most recent are kept.", object{"type": "integer", "minimum": 1}),
+					},
+				}, http.StatusOK, object{"type": "array", "items": ref("AuditEntry")}, http.StatusBadRequest, http.StatusNotFound),
+			},
+			"/task-types": object{
+				"get": operation("listTaskTypes", "List registered task types", nil,
+					http.StatusOK, object{"type": "array", "items": ref("TaskType")}),
+			},
+			"/openapi.json": object{
+				"get": operation("getOpenAPI", "Get this OpenAPI document", object{"security": []any{}},
+					http.StatusOK, object{"type": "object"}),
+			},
+		},
+		"components": object{
+			"schemas": object{
+				"Task":              taskSchema(),
+				"TaskStatus":        object{"type": "string", "enum": []string{"pending", "running", "done", "failed", "canceled"}},
+				"TaskType":          taskTypeSchema(),
+				"CreateTaskRequest": createTaskRequestSchema(types),
+				"CreateBatchRequest": object{
+					"type":     "object",
+					"required": []string{"tasks"},
+					"properties": object{
+						"mode":  ref("BatchMode"),
+						"tasks": object{"type": "array", "minItems": 1, "items": ref("CreateTaskRequest")},
+					},
+				},
+				"BatchMode":   object{"type": "string", "enum": []string{"atomic", "best_effort"}, "default": "atomic"},
+				"BatchResult": batchResultSchema(),
+				"Batch":       batchSchema(),
+				"DeleteTasksResult": object{
+					"type":     "object",
+					"required": []string{"dry_run", "count", "task_ids"},
+					"properties": object{
+						"dry_run":  object{"type": "boolean"},
+						"count":    object{"type": "integer", "minimum": 0},
+						"task_ids": object{"type": "array", "items": object{"type": "string"}},
+					},
+				},
+				"Queue": queueSchema(),
+				"DeadLetter": object{
+					"type":     "object",
+					"required": []string{"task", "dead_at", "errors"},
+					"properties": object{
+						"task":    ref("Task"),
+						"dead_at": object{"type": "string", "format": "date-time"},
+						"errors": object{
+							"type": "array",
+							"items": object{
+								"type":     "object",
+								"required": []string{"attempt", "failed_at", "error"},
+								"properties": object{
+									"attempt":   object{"type": "integer", "minimum": 1},
+									"failed_at": object{"type": "string", "format": "date-time"},
+									"error":     object{"type": "string"},
+								},
+							},
+						},
+					},
+				},
+				"PurgeQueueResult": object{
+					"type":     "object",
+					"required": []string{"type", "count", "task_ids"},
+					"properties": object{
+						"type":     object{"type": "string"},
+						"count":    object{"type": "integer", "minimum": 0},
+						"task_ids": object{"type": "array", "items": object{"type": "string"}},
+					},
+				},
+				"AuditEntry": auditEntrySchema(),
+				"Problem":    problemSchema(),
+			},
+			"securitySchemes": object{
+				"ApiKey": object{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"BearerAuth": object{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "API key or JWT verified against the configured JWKS.",
+				},
+			},
+			"responses": object{
+				"Problem": object{
+					"description": "Error described by RFC 7807 problem details.",
+					"content":     object{"application/problem+json": object{"schema": ref("Problem")}},
+					"headers": object{
+						"Retry-After": object{
+							"description": "Seconds until a client over its rate limit may create tasks again (rate_limited).",
+							"schema":      object{"type": "integer"},
+						},
+						"X-RateLimit-Limit": object{
+							"description": "Tasks the client may create per second (rate_limited, burst_exceeded).",
+							"schema":      object{"type": "number"},
+						},
+						"X-RateLimit-Burst": object{
+							"description": "Tasks the client may create at once (rate_limited, burst_exceeded).",
+							"schema":      object{"type": "integer"},
+						},
+						"X-Quota-Limit": object{
+							"description": "Pending tasks the client may have (quota_exceeded).",
+							"schema":      object{"type": "integer"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// operation describes an operation with its success response and the documented error statuses.
+// Extra fields such as parameters or the request body are merged into the result.
+func operation(id, summary string, extra object, status int, schema any, errorStatuses ...int) object {
+	success := object{"description": http.StatusText(status)}
+	if schema != nil {
+		success["content"] = jsonContent(schema)
+	}
+
+	responses := object{
+		strconv.Itoa(status): success,
+		"default":            object{"$ref": "#/components/responses/Problem"},
+	}
+	for _, s := range errorStatuses {
+		responses[strconv.Itoa(s)] = object{"$ref": "#/components/responses/Problem"}
+	}
+
+	op := object{"operationId": id, "summary": summary, "responses": responses}
+	for k, v := range extra {
+		op[k] = v
+	}
+
+	return op
+}
+
+// idParameter describes the ID path parameter of a task or batch.
+func idParameter() object {
+	return object{"name": "id", "in": "path", "required": true, "schema": object{"type": "string"}}
+}
+
+// typeParameter describes the task type path parameter.
+func typeParameter() object {
+	return object{"name": "type", "in": "path", "required": true, "schema": object{"type": "string"}}
+}
+
+// filterParameters describes the query parameters selecting tasks by status, type, and age.
+func filterParameters() []any {
+	return []any{
+		queryParameter("status", "Only tasks with this status.", ref("TaskStatus")),
+		queryParameter("type", "Only tasks of this type.", object{"type": "string"}),
+		queryParameter("older_than", "Only tasks created longer ago than this duration (e.g. \"1h\").", object{"type": "string"}),
+	}
+}
+
+// queryParameter describes an optional query parameter.
+func queryParameter(name, description string, schema any) object {
+	return object{"name": name, "in": "query", "description": description, "schema": schema}
+}
+
+// jsonContent describes an "application/json" body with the given schema.
+func jsonContent(schema any) object {
+	return object{"application/json": object{"schema": schema}}
+}
+
+// ref returns a reference to a schema of the components section.
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+// taskSchema describes model.Task.
+func taskSchema() object {
+	timestamp := object{"type": "string", "format": "date-time"}
+
+	return object{
+		"type":     "object",
+		"required": []string{"id", "type", "status", "created_at", "queued_duration_ms", "run_duration_ms"},
+		"properties": object{
+			"id":                 object{"type": "string"},
+			"type":               object{"type": "string"},
+			"status":             ref("TaskStatus"),
+			"created_at":         timestamp,
+			"started_at":         timestamp,
+			"finished_at":        timestamp,
+			"queued_duration_ms": object{"type": "integer", "minimum": 0},
+			"run_duration_ms":    object{"type": "integer", "minimum": 0},
+			"duration":           object{"type": "string"},
+			"result":             object{"type": "string"},
+			"params":             object{"description": "Type-specific task parameters."},
+			"output":             object{"description": "Structured output produced by the task."},
+			"batch_id":           object{"type": "string"},
+			"owner":              object{"type": "string"},
+			"request_id":         object{"type": "string", "description": "ID of the request that queued the task."},
+			"traceparent":        object{"type": "string", "description": "W3C trace context of the request that queued the task."},
+			"retry_of":           object{"type": "string"},
+			"attempts": object{
+				"type": "array",
+				"items": object{
+					"type":     "object",
+					"required": []string{"queued_at", "status"},
+					"properties": object{
+						"queued_at":   timestamp,
+						"started_at":  timestamp,
+						"finished_at": timestamp,
+						"status":      ref("TaskStatus"),
+						"result":      object{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// batchResultSchema describes the response of the batch create request.
+func batchResultSchema() object {
+	return object{
+		"type":     "object",
+		"required": []string{"mode", "items"},
+		"properties": object{
+			"batch_id": object{"type": "string", "description": "Omitted if no task of a best-effort batch was created."},
+			"mode":     ref("BatchMode"),
+			"items": object{
+				"type": "array",
+				"items": object{
+					"type":     "object",
+					"required": []string{"index"},
+					"properties": object{
+						"index": object{"type": "integer", "minimum": 0},
+						"task":  ref("Task"),
+						"error": object{
+							"type": "object",
+							"properties": object{
+								"status": object{"type": "integer"},
+								"code":   object{"type": "string"},
+								"detail": object{"type": "string"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// batchSchema describes model.Batch.
+func batchSchema() object {
+	counter := object{"type": "integer", "minimum": 0}
+
+	return object{
+		"type":     "object",
+		"required": []string{"id", "mode", "created_at", "total", "stats", "finished", "task_ids"},
+		"properties": object{
+			"id":         object{"type": "string"},
+			"mode":       ref("BatchMode"),
+			"owner":      object{"type": "string"},
+			"created_at": object{"type": "string", "format": "date-time"},
+			"total":      counter,
+			"finished":   object{"type": "boolean"},
+			"task_ids":   object{"type": "array", "items": object{"type": "string"}},
+			"stats": object{
+				"type": "object",
+				"properties": object{
+					"pending":  counter,
+					"running":  counter,
+					"done":     counter,
+					"failed":   counter,
+					"canceled": counter,
+					"deleted":  counter,
+				},
+			},
+		},
+	}
+}
+
+// queueSchema describes model.Queue.
+func queueSchema() object {
+	counter := object{"type": "integer", "minimum": 0}
+
+	return object{
+		"type": "object",
+		"required": []string{"type", "paused", "reject_new", "depth", "active", "running",
+			"queue_size", "concurrency", "oldest_pending_ms", "dead_letters"},
+		"properties": object{
+			"type":              object{"type": "string"},
+			"paused":            object{"type": "boolean"},
+			"paused_at":         object{"type": "string", "format": "date-time"},
+			"reject_new":        object{"type": "boolean"},
+			"depth":             counter,
+			"active":            counter,
+			"running":           counter,
+			"queue_size":        counter,
+			"concurrency":       counter,
+			"oldest_pending_ms": counter,
+			"dead_letters":      counter,
+			"throughput": object{
+				"type": "array",
+				"items": object{
+					"type": "object",
+					"properties": object{
+						"window":   object{"type": "string"},
+						"done":     counter,
+						"failed":   counter,
+						"canceled": counter,
+					},
+				},
+			},
+		},
+	}
+}
+
+// taskTypeSchema describes model.TaskType.
+func taskTypeSchema() object {
+	counter := object{"type": "integer", "minimum": 0}
+
+	return object{
+		"type":     "object",
+		"required": []string{"type", "disabled", "queue_size", "concurrency", "timeout_ms", "stats"},
+		"properties": object{
+			"type":          object{"type": "string"},
+			"description":   object{"type": "string"},
+			"params_schema": object{"type": "object"},
+			"disabled":      object{"type": "boolean"},
+			"queue_size":    counter,
+			"concurrency":   counter,
+			"timeout_ms":    counter,
+			"stats": object{
+				"type": "object",
+				"properties": object{
+					"pending":  counter,
+					"running":  counter,
+					"done":     counter,
+					"failed":   counter,
+					"canceled": counter,
+				},
+			},
+		},
+	}
+}
+
+// createTaskRequestSchema describes the create request body with one variant per task type,
+// each pairing the type name with the schema of its parameters.
+func createTaskRequestSchema(types []*model.TaskType) object {
+	variants := make([]any, 0, len(types))
+	for _, t := range types {
+		params := any(object{})
+		if len(t.ParamsSchema) > 0 {
+			params = json.RawMessage(t.ParamsSchema)
+		}
+
+		variants = append(variants, object{
+			"title":    t.Type,
+			"type":     "object",
+			"required": []string{"type"},
+			"properties": object{
+				"type":   object{"const": t.Type},
+				"params": params,
+			},
+		})
+	}
+
+	schema := object{
+		"type":       "object",
+		"properties": object{"type": object{"type": "string"}, "params": object{}},
+	}
+	if len(variants) > 0 {
+		schema["oneOf"] = variants
+	}
+
+	return schema
+}
+
+// auditEntrySchema describes model.AuditEntry.
+func auditEntrySchema() object {
+	return object{
+		"type":     "object",
+		"required": []string{"time", "actor", "action", "task_type"},
+		"properties": object{
+			"time":       object{"type": "string", "format": "date-time"},
+			"actor":      object{"type": "string"},
+			"action":     object{"type": "string", "enum": []string{"create", "delete", "cancel", "retry", "requeue", "discard", "purge", "pause", "resume"}},
+			"task_id":    object{"type": "string", "description": "Missing for queue operations."},
+			"task_type":  object{"type": "string"},
+			"retry_of":   object{"type": "string"},
+			"before":     object{"$ref": "#/components/schemas/TaskStatus", "description": "Missing if the task did not exist."},
+			"after":      object{"$ref": "#/components/schemas/TaskStatus", "description": "Missing if the task was removed."},
+			"request_id": object{"type": "string"},
+		},
+	}
+}
+
+// problemSchema describes response.Problem.
+func problemSchema() object {
+	return object{
+		"type":     "object",
+		"required": []string{"type", "title", "status", "code"},
+		"properties": object{
+			"type":       object{"type": "string"},
+			"title":      object{"type": "string"},
+			"status":     object{"type": "integer"},
+			"detail":     object{"type": "string"},
+			"instance":   object{"type": "string"},
+			"code":       object{"type": "string"},
+			"request_id": object{"type": "string"},
+		},
+	}
+}
@@ -7,4 +7,22 @@ const (
 
 	// ErrMethodNotAllowed is returned when an HTTP method is not supported for the requested endpoint.
 	ErrMethodNotAllowed = "method not allowed"
+
+	// ErrNotFound is returned when no endpoint matches the requested path.
+	ErrNotFound = "not found"
+
+	// ErrInvalidQuery is returned when a query parameter has an unsupported value.
+	ErrInvalidQuery = "invalid query parameter"
+
+	// ErrInvalidRequestBody is returned when the request body cannot be decoded.
+	ErrInvalidRequestBody = "invalid request body"
+
+	// ErrRequestTooLarge is returned when the request body exceeds the size limit of the server.
+	ErrRequestTooLarge = "request body too large"
+
+	// ErrForbidden is returned when the authenticated client lacks the scope required by the endpoint.
+	ErrForbidden = "insufficient scope"
+
+	// ErrAuditDisabled is returned when the audit log is queried but not enabled on the server.
+	ErrAuditDisabled = "audit log disabled"
 )
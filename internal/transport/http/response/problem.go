@@ -0,0 +1,86 @@
+package response
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+// RequestIDHeader is the header carrying the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// Stable machine-readable error codes returned in the "code" field of problem responses.
+const (
+	CodeInvalidRequestBody = "invalid_request_body"
+	CodeInvalidQuery       = "invalid_query"
+	CodeRequestTooLarge    = "request_too_large"
+	CodeUnknownTaskType    = "unknown_task_type"
+	CodeInvalidParams      = "invalid_params"
+	CodeTaskTypeDisabled   = "task_type_disabled"
+	CodeTaskAlreadyExists  = "task_already_exists"
+	CodeQueueLimitReached  = "queue_limit_reached"
+	CodeTaskNotFound       = "task_not_found"
+	CodeTaskInProgress     = "task_in_progress"
+	CodeTaskFinished       = "task_finished"
+	CodeShuttingDown       = "shutting_down"
+	CodeInvalidBatch       = "invalid_batch"
+	CodeBatchNotFound      = "batch_not_found"
+	CodeQueuePaused        = "queue_paused"
+	CodeTaskNotFinished    = "task_not_finished"
+	CodeDeadLetterNotFound = "dead_letter_not_found"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeRateLimited        = "rate_limited"
+	CodeQuotaExceeded      = "quota_exceeded"
+	CodeBurstExceeded      = "burst_exceeded"
+	CodeAuditFailed        = "audit_failed"
+	CodeAuditDisabled      = "audit_disabled"
+	CodeNotFound           = "not_found"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeInternal           = "internal_error"
+)
+
+// Problem is an RFC 7807 problem details object extended with a stable error code and the request ID.
+type Problem struct {
+	Type      string `json:"type"`                 // Problem type URI ("about:blank" means the status describes it)
+	Title     string `json:"title"`                // Short summary of the problem type
+	Status    int    `json:"status"`               // HTTP status code
+	Detail    string `json:"detail,omitempty"`     // Human-readable explanation of this occurrence
+	Instance  string `json:"instance,omitempty"`   // Request path the problem occurred on
+	Code      string `json:"code"`                 // Stable machine-readable error code
+	RequestID string `json:"request_id,omitempty"` // ID of the failed request
+}
+
+// RespondProblem sends an "application/problem+json" response with the given status, code, and detail.
+// The request ID is taken from the request and echoed in the response headers.
+func RespondProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	id := RequestID(r)
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set(RequestIDHeader, id)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: id,
+	})
+}
+
+// RequestID returns the ID sent by the client in the X-Request-ID header,
+// or a new random one if the header is missing.
+func RequestID(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); id != "" {
+		return id
+	}
+
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
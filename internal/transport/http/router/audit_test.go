@@ -0,0 +1,89 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/audit"
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+// TestAudit checks that operations are audited with the identity of the client and served to admins by task,
+// and that operations fail once the audit log cannot be written.
+func TestAudit(t *testing.T) {
+	log, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("cannot open audit log: %v", err)
+	}
+
+	manager := service.NewTaskManager(service.WithAuditor(log))
+	manager.RegisterFactory("wait", blockingFactory{})
+	t.Cleanup(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_ = manager.Shutdown(ctx)
+	})
+
+	a, err := auth.New(auth.Config{APIKeys: []auth.APIKey{
+		{Name: "alice", Hash: auth.HashKey("alice-key"), Scopes: []string{auth.CreateScope("wait"), auth.ScopeTasksDelete}},
+		{Name: "root", Hash: auth.HashKey("root-key"), Scopes: []string{auth.ScopeAdmin}},
+	}})
+	if err != nil {
+		t.Fatalf("cannot create authenticator: %v", err)
+	}
+	taskHandler := handler.NewTaskHandler(manager)
+	taskHandler.AuditLog = log
+	h := a.Middleware(router.InitTaskRouter(taskHandler))
+
+	var created model.Task
+	_ = json.NewDecoder(call(h, "alice-key", http.MethodPost, "/tasks?type=wait", "").Body).Decode(&created)
+	call(h, "alice-key", http.MethodPost, "/tasks?type=wait", "")
+	if rec := call(h, "alice-key", http.MethodPost, "/tasks/"+created.ID+"/cancel", ""); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+	}
+
+	if rec := call(h, "alice-key", http.MethodGet, "/audit", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a client without the admin scope, got %d", rec.Code)
+	}
+
+	rec := call(h, "root-key", http.MethodGet, "/audit?task_id="+created.ID, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var entries []model.AuditEntry
+	_ = json.NewDecoder(rec.Body).Decode(&entries)
+	if len(entries) != 2 || entries[0].Action != model.AuditActionCreate || entries[1].Action != model.AuditActionCancel {
+		t.Fatalf("expected the creation and cancellation of the task, got %+v", entries)
+	}
+	if entries[1].Actor != "alice" {
+		t.Errorf("expected alice to cancel the task, got %+v", entries[1])
+	}
+
+	if rec := call(h, "root-key", http.MethodGet, "/audit?limit=0", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid limit, got %d", rec.Code)
+	}
+	if rec := call(h, "root-key", http.MethodGet, "/audit?since=yesterday", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid since, got %d", rec.Code)
+	}
+	rec = call(h, "root-key", http.MethodGet, "/audit?since=2999-01-01T00:00:00Z", "")
+	if body := strings.TrimSpace(rec.Body.String()); rec.Code != http.StatusOK || body != "[]" {
+		t.Errorf("expected no entries after a future since, got %d %s", rec.Code, body)
+	}
+
+	_ = log.Close()
+	rec = call(h, "alice-key", http.MethodPost, "/tasks?type=wait", "")
+	var problem response.Problem
+	_ = json.NewDecoder(rec.Body).Decode(&problem)
+	if rec.Code != http.StatusServiceUnavailable || problem.Code != response.CodeAuditFailed {
+		t.Errorf("expected 503 %s, got %d %s", response.CodeAuditFailed, rec.Code, problem.Code)
+	}
+}
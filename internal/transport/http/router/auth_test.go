@@ -0,0 +1,179 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/transport/http/auth"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+type (
+	blockingFactory struct{} // blockingFactory creates tasks that run until canceled.
+	blockingTask    struct{} // blockingTask blocks until its context is done.
+)
+
+// New returns a task blocking until canceled.
+func (blockingFactory) New(_ *model.Task) task.ExecutableTask {
+	return blockingTask{}
+}
+
+// Run blocks until ctx is done.
+func (blockingTask) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// newAuthRouter returns the task router behind the authenticator, with a "wait" task type and three clients:
+// "alice" and "bob" may create "wait" tasks and read and delete their own, "root" is an admin.
+func newAuthRouter(t *testing.T, opts ...service.Option) http.Handler {
+	t.Helper()
+
+	manager := service.NewTaskManager(opts...)
+	manager.RegisterFactory("wait", blockingFactory{})
+	t.Cleanup(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_ = manager.Shutdown(ctx)
+	})
+
+	client := []string{auth.CreateScope("wait"), auth.ScopeTasksRead, auth.ScopeTasksDelete}
+	a, err := auth.New(auth.Config{APIKeys: []auth.APIKey{
+		{Name: "alice", Hash: auth.HashKey("alice-key"), Scopes: client},
+		{Name: "bob", Hash: auth.HashKey("bob-key"), Scopes: client},
+		{Name: "root", Hash: auth.HashKey("root-key"), Scopes: []string{auth.ScopeAdmin}},
+	}})
+	if err != nil {
+		t.Fatalf("cannot create authenticator: %v", err)
+	}
+
+	return a.Middleware(router.InitTaskRouter(handler.NewTaskHandler(manager)))
+}
+
+// call sends a request with the API key and returns the recorded response.
+func call(h http.Handler, key, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(auth.APIKeyHeader, key)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// TestAuth_Ownership checks that clients only see and cancel their own tasks unless they are admins.
+func TestAuth_Ownership(t *testing.T) {
+	h := newAuthRouter(t)
+
+	rec := call(h, "alice-key", http.MethodPost, "/tasks?type=wait", "")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	var created model.Task
+	_ = json.NewDecoder(rec.Body).Decode(&created)
+	if created.Owner != "alice" {
+		t.Fatalf("expected owner alice, got %q", created.Owner)
+	}
+
+	if rec := call(h, "bob-key", http.MethodGet, "/tasks/"+created.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another owner's task, got %d", rec.Code)
+	}
+	if rec := call(h, "bob-key", http.MethodPost, "/tasks/"+created.ID+"/cancel", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 when canceling another owner's task, got %d", rec.Code)
+	}
+
+	var listed []model.Task
+	_ = json.NewDecoder(call(h, "bob-key", http.MethodGet, "/tasks", "").Body).Decode(&listed)
+	if len(listed) != 0 {
+		t.Errorf("expected bob to see no tasks, got %d", len(listed))
+	}
+
+	if rec := call(h, "root-key", http.MethodGet, "/tasks/"+created.ID, ""); rec.Code != http.StatusOK {
+		t.Errorf("expected admin to read any task, got %d", rec.Code)
+	}
+	if rec := call(h, "alice-key", http.MethodPost, "/tasks/"+created.ID+"/cancel", ""); rec.Code != http.StatusAccepted {
+		t.Errorf("expected owner to cancel the task, got %d", rec.Code)
+	}
+}
+
+// TestAuth_Scopes checks that endpoints outside the granted scopes are forbidden.
+func TestAuth_Scopes(t *testing.T) {
+	h := newAuthRouter(t)
+
+	cases := []struct {
+		name   string
+		key    string
+		method string
+		path   string
+		status int
+	}{
+		{"no credentials", "", http.MethodGet, "/tasks", http.StatusUnauthorized},
+		{"create other type", "alice-key", http.MethodPost, "/tasks?type=default", http.StatusForbidden},
+		{"bulk delete", "alice-key", http.MethodDelete, "/tasks?status=done", http.StatusForbidden},
+		{"queues", "alice-key", http.MethodGet, "/queues", http.StatusForbidden},
+		{"dead letters", "alice-key", http.MethodGet, "/dead-letters", http.StatusForbidden},
+		{"admin queues", "root-key", http.MethodGet, "/queues", http.StatusOK},
+		{"public document", "", http.MethodGet, "/openapi.json", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := call(h, tc.key, tc.method, tc.path, "")
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body)
+			}
+
+			if tc.status >= http.StatusBadRequest {
+				var p response.Problem
+				_ = json.NewDecoder(rec.Body).Decode(&p)
+				if p.Status != tc.status || p.Code == "" {
+					t.Errorf("unexpected problem: %+v", p)
+				}
+			}
+		})
+	}
+}
+
+// TestAuth_ClientLimits checks that exceeded client limits are reported in 429 responses.
+func TestAuth_ClientLimits(t *testing.T) {
+	h := newAuthRouter(t, service.WithClientLimits(
+		service.ClientLimits{Rate: 0.5, Burst: 1},
+		map[string]service.ClientLimits{"bob": {Rate: 100, MaxPending: 1}},
+	))
+
+	if rec := call(h, "alice-key", http.MethodPost, "/tasks?type=wait", ""); rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	rec := call(h, "alice-key", http.MethodPost, "/tasks?type=wait", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" || rec.Header().Get("X-RateLimit-Limit") != "0.5" {
+		t.Errorf("expected 429 with Retry-After 2 and the rate limit, got %d %v", rec.Code, rec.Header())
+	}
+	var p response.Problem
+	_ = json.NewDecoder(rec.Body).Decode(&p)
+	if p.Code != response.CodeRateLimited {
+		t.Errorf("expected code %q, got %+v", response.CodeRateLimited, p)
+	}
+
+	// A batch larger than the burst could never go through, so it is refused without Retry-After.
+	rec = call(h, "alice-key", http.MethodPost, "/tasks:batch", `{"tasks":[{"type":"wait"},{"type":"wait"}]}`)
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Retry-After") != "" || rec.Header().Get("X-RateLimit-Burst") != "1" {
+		t.Errorf("expected 400 with the burst and without Retry-After, got %d %v", rec.Code, rec.Header())
+	}
+
+	// Only pending tasks count: whether or not the first task has started, the third one is over the quota.
+	for range 2 {
+		call(h, "bob-key", http.MethodPost, "/tasks?type=wait", "")
+	}
+	rec = call(h, "bob-key", http.MethodPost, "/tasks?type=wait", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("X-Quota-Limit") != "1" {
+		t.Errorf("expected 429 with the quota, got %d %v", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,113 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+// probeMethods are the methods tried against every documented path.
+var probeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// openAPIDocument is the part of the OpenAPI document checked by the tests.
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			OneOf []struct {
+				Title string `json:"title"`
+			} `json:"oneOf"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+// fetchSpec requests the OpenAPI document from the router.
+func fetchSpec(t *testing.T, h http.Handler) openAPIDocument {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var doc openAPIDocument
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("cannot decode spec: %v", err)
+	}
+	return doc
+}
+
+// TestOpenAPI_MatchesRoutes checks that the spec documents exactly the routes served by the router.
+func TestOpenAPI_MatchesRoutes(t *testing.T) {
+	h := newRouter()
+	doc := fetchSpec(t, h)
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		t.Errorf("expected OpenAPI 3.1, got %q", doc.OpenAPI)
+	}
+
+	var documented []router.Route
+	for path, item := range doc.Paths {
+		for key := range item {
+			if key != "parameters" {
+				documented = append(documented, router.Route{Method: strings.ToUpper(key), Path: path})
+			}
+		}
+	}
+
+	routes := router.Routes()
+	for _, r := range routes {
+		if !slices.Contains(documented, r) {
+			t.Errorf("route %s %s is not documented", r.Method, r.Path)
+		}
+	}
+	for _, r := range documented {
+		if !slices.Contains(routes, r) {
+			t.Errorf("documented operation %s %s is not a route", r.Method, r.Path)
+		}
+	}
+
+	for path := range doc.Paths {
+		target := strings.NewReplacer("{id}", "x", "{type}", "x").Replace(path)
+
+		for _, method := range probeMethods {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+
+			var p response.Problem
+			_ = json.NewDecoder(rec.Body).Decode(&p)
+			served := rec.Code != http.StatusMethodNotAllowed && p.Code != response.CodeNotFound
+
+			if want := slices.Contains(routes, router.Route{Method: method, Path: path}); served != want {
+				t.Errorf("%s %s: expected served=%t, got status %d", method, target, want, rec.Code)
+			}
+		}
+	}
+}
+
+// TestOpenAPI_TaskTypeSchemas checks that registered task types become variants of the create request.
+func TestOpenAPI_TaskTypeSchemas(t *testing.T) {
+	manager := service.NewTaskManager()
+	manager.Register(task.Descriptor{
+		Type:         task.ExecTaskType,
+		ParamsSchema: json.RawMessage(task.ExecTaskParamsSchema),
+		Factory:      &task.ExecTaskFactory{},
+	}, service.DefaultTypeConfig())
+
+	doc := fetchSpec(t, router.InitTaskRouter(handler.NewTaskHandler(manager)))
+
+	variants := doc.Components.Schemas["CreateTaskRequest"].OneOf
+	if len(variants) != 1 || variants[0].Title != task.ExecTaskType {
+		t.Errorf("expected one %q variant, got %+v", task.ExecTaskType, variants)
+	}
+}
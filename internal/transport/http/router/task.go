@@ -2,28 +2,94 @@ package router
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/kylerqws/task-runner/internal/transport/http/handler"
 	"github.com/kylerqws/task-runner/internal/transport/http/response"
 )
 
+// Route is an endpoint served by the router, with the path written as an OpenAPI template.
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Routes returns every endpoint registered by InitTaskRouter.
+// It must be kept in sync with the handlers below; the OpenAPI document is checked against it.
+func Routes() []Route {
+	return []Route{
+		{http.MethodGet, "/tasks"},
+		{http.MethodPost, "/tasks"},
+		{http.MethodDelete, "/tasks"},
+		{http.MethodGet, "/tasks/{id}"},
+		{http.MethodDelete, "/tasks/{id}"},
+		{http.MethodPost, "/tasks/{id}/cancel"},
+		{http.MethodPost, "/tasks/{id}/retry"},
+		{http.MethodPost, "/tasks:batch"},
+		{http.MethodGet, "/batches/{id}"},
+		{http.MethodGet, "/queues"},
+		{http.MethodGet, "/queues/{type}"},
+		{http.MethodPost, "/queues/{type}:purge"},
+		{http.MethodPost, "/queues/{type}/pause"},
+		{http.MethodPost, "/queues/{type}/resume"},
+		{http.MethodGet, "/dead-letters"},
+		{http.MethodGet, "/dead-letters/{id}"},
+		{http.MethodDelete, "/dead-letters/{id}"},
+		{http.MethodPost, "/dead-letters/{id}/requeue"},
+		{http.MethodGet, "/audit"},
+		{http.MethodGet, "/task-types"},
+		{http.MethodGet, "/openapi.json"},
+	}
+}
+
 // InitTaskRouter initializes HTTP routing for task-related endpoints.
-// It registers routes for creating, retrieving, and deleting tasks.
+// It registers routes for creating, listing, retrieving, cancelling, and deleting tasks, for batch submission,
+// for queue management, for dead letters, for the audit log, for task type discovery, and for the OpenAPI document.
 func InitTaskRouter(taskHandler *handler.TaskHandler) http.Handler {
 	mux := http.NewServeMux()
 
-	// POST /tasks
+	// GET /tasks, POST /tasks, DELETE /tasks
 	mux.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			taskHandler.List(w, r)
+			return
+		}
+
 		if r.Method == http.MethodPost {
 			taskHandler.Create(w, r)
 			return
 		}
 
-		http.Error(w, response.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
+		if r.Method == http.MethodDelete {
+			taskHandler.DeleteMany(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
 	})
 
-	// GET /tasks/{id}, DELETE /tasks/{id}
+	// GET /tasks/{id}, DELETE /tasks/{id}, POST /tasks/{id}/cancel, POST /tasks/{id}/retry
 	mux.HandleFunc("/tasks/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cancel") {
+			if r.Method == http.MethodPost {
+				taskHandler.Cancel(w, r)
+				return
+			}
+
+			methodNotAllowed(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/retry") {
+			if r.Method == http.MethodPost {
+				taskHandler.Retry(w, r)
+				return
+			}
+
+			methodNotAllowed(w, r)
+			return
+		}
+
 		if r.Method == http.MethodGet {
 			taskHandler.Get(w, r)
 			return
@@ -34,8 +100,146 @@ func InitTaskRouter(taskHandler *handler.TaskHandler) http.Handler {
 			return
 		}
 
-		http.Error(w, response.ErrMethodNotAllowed, http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
+	})
+
+	// POST /tasks:batch
+	mux.HandleFunc("/tasks:batch", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			taskHandler.CreateBatch(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /batches/{id}
+	mux.HandleFunc("/batches/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			taskHandler.GetBatch(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /queues
+	mux.HandleFunc("/queues", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			taskHandler.ListQueues(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /queues/{type}, POST /queues/{type}:purge, POST /queues/{type}/pause, POST /queues/{type}/resume
+	mux.HandleFunc("/queues/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/queues/")
+
+		for suffix, action := range map[string]http.HandlerFunc{
+			":purge":  taskHandler.Purge,
+			"/pause":  taskHandler.Pause,
+			"/resume": taskHandler.Resume,
+		} {
+			if strings.HasSuffix(path, suffix) {
+				if r.Method == http.MethodPost {
+					action(w, r)
+					return
+				}
+
+				methodNotAllowed(w, r)
+				return
+			}
+		}
+
+		if strings.Contains(path, "/") {
+			response.RespondProblem(w, r, http.StatusNotFound, response.CodeNotFound, response.ErrNotFound)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			taskHandler.GetQueue(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /dead-letters
+	mux.HandleFunc("/dead-letters", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			taskHandler.ListDeadLetters(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /dead-letters/{id}, DELETE /dead-letters/{id}, POST /dead-letters/{id}/requeue
+	mux.HandleFunc("/dead-letters/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/requeue") {
+			if r.Method == http.MethodPost {
+				taskHandler.RequeueDeadLetter(w, r)
+				return
+			}
+
+			methodNotAllowed(w, r)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			taskHandler.GetDeadLetter(w, r)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			taskHandler.DiscardDeadLetter(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /audit
+	mux.HandleFunc("/audit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			taskHandler.ListAudit(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /task-types
+	mux.HandleFunc("/task-types", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			taskHandler.ListTypes(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// GET /openapi.json
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			taskHandler.OpenAPI(w, r)
+			return
+		}
+
+		methodNotAllowed(w, r)
+	})
+
+	// Any other path
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		response.RespondProblem(w, r, http.StatusNotFound, response.CodeNotFound, response.ErrNotFound)
 	})
 
 	return mux
 }
+
+// methodNotAllowed sends a problem response for an unsupported method on a known route.
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	response.RespondProblem(w, r, http.StatusMethodNotAllowed, response.CodeMethodNotAllowed, response.ErrMethodNotAllowed)
+}
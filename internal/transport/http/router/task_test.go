@@ -0,0 +1,87 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/response"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+// newRouter returns the task router backed by an empty task manager.
+func newRouter() http.Handler {
+	return router.InitTaskRouter(handler.NewTaskHandler(service.NewTaskManager()))
+}
+
+// TestProblemResponses checks that errors from handlers and the router share the problem envelope.
+func TestProblemResponses(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"unknown path", http.MethodGet, "/unknown", "", http.StatusNotFound, response.CodeNotFound},
+		{"method not allowed", http.MethodPut, "/tasks", "", http.StatusMethodNotAllowed, response.CodeMethodNotAllowed},
+		{"task not found", http.MethodGet, "/tasks/missing", "", http.StatusNotFound, response.CodeTaskNotFound},
+		{"unknown type", http.MethodPost, "/tasks?type=unknown", "", http.StatusBadRequest, response.CodeUnknownTaskType},
+		{"invalid status", http.MethodGet, "/tasks?status=unknown", "", http.StatusBadRequest, response.CodeInvalidQuery},
+		{"invalid body", http.MethodPost, "/tasks", "{", http.StatusBadRequest, response.CodeInvalidRequestBody},
+		{"empty batch", http.MethodPost, "/tasks:batch", `{"tasks":[]}`, http.StatusBadRequest, response.CodeInvalidBatch},
+		{"bulk delete without filter", http.MethodDelete, "/tasks", "", http.StatusBadRequest, response.CodeInvalidQuery},
+		{"invalid older_than", http.MethodDelete, "/tasks?older_than=soon", "", http.StatusBadRequest, response.CodeInvalidQuery},
+		{"purge unknown type", http.MethodPost, "/queues/unknown:purge", "", http.StatusBadRequest, response.CodeUnknownTaskType},
+		{"unknown queue", http.MethodGet, "/queues/unknown", "", http.StatusBadRequest, response.CodeUnknownTaskType},
+		{"unknown queue action", http.MethodPost, "/queues/default/drain", "", http.StatusNotFound, response.CodeNotFound},
+		{"retry missing task", http.MethodPost, "/tasks/missing/retry", "", http.StatusNotFound, response.CodeTaskNotFound},
+		{"dead letter not found", http.MethodGet, "/dead-letters/missing", "", http.StatusNotFound, response.CodeDeadLetterNotFound},
+		{"batch not found", http.MethodGet, "/batches/missing", "", http.StatusNotFound, response.CodeBatchNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set(response.RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+
+			newRouter().ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("expected problem content type, got %q", ct)
+			}
+
+			var p response.Problem
+			if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+				t.Fatalf("cannot decode problem: %v", err)
+			}
+			if p.Code != tc.code || p.Status != tc.status || p.RequestID != "req-1" {
+				t.Errorf("unexpected problem: %+v", p)
+			}
+		})
+	}
+}
+
+// TestRequestTooLarge checks that bodies over the size limit of the server get a 413 problem response.
+func TestRequestTooLarge(t *testing.T) {
+	h := http.MaxBytesHandler(newRouter(), 16)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"type":"default","params":{"n":1}}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var p response.Problem
+	_ = json.NewDecoder(rec.Body).Decode(&p)
+	if rec.Code != http.StatusRequestEntityTooLarge || p.Code != response.CodeRequestTooLarge {
+		t.Errorf("expected 413 %s, got %d %+v", response.CodeRequestTooLarge, rec.Code, p)
+	}
+}
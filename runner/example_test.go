@@ -0,0 +1,31 @@
+package runner_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kylerqws/task-runner/runner"
+)
+
+// Example registers a custom task type, submits a task, and waits for its result.
+func Example() {
+	r := runner.New(runner.WithDefaultLimits(runner.Limits{QueueSize: 10, Concurrency: 2}))
+	defer func() { _ = r.Shutdown(context.Background()) }()
+
+	if err := r.Register("upper", &upperFactory{}); err != nil {
+		panic(err)
+	}
+
+	t, err := r.Submit("upper", map[string]string{"text": "hello"})
+	if err != nil {
+		panic(err)
+	}
+
+	t, err = r.Wait(context.Background(), t.ID)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(t.Status, string(t.Output))
+	// Output: done {"result":"HELLO"}
+}
@@ -0,0 +1,159 @@
+// Package runner embeds the task runner in another Go program: custom task types are registered
+// in-process, tasks are submitted and awaited directly, and the HTTP API can be mounted on any mux.
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+	"github.com/kylerqws/task-runner/internal/transport/http/handler"
+	"github.com/kylerqws/task-runner/internal/transport/http/router"
+)
+
+// Runner queues and executes tasks of registered types.
+type Runner struct {
+	manager  *service.TaskManager
+	defaults Limits
+	closed   atomic.Bool
+}
+
+// Option configures a Runner.
+type Option func(*Runner)
+
+// New returns a Runner configured with the given options.
+func New(opts ...Option) *Runner {
+	r := &Runner{manager: service.NewTaskManager(), defaults: service.DefaultTypeConfig()}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithDefaultLimits sets the limits of task types registered without WithLimits.
+func WithDefaultLimits(limits Limits) Option {
+	return func(r *Runner) {
+		r.defaults = limits
+	}
+}
+
+// RegisterOption configures a task type registered with Register.
+type RegisterOption func(*registration)
+
+// registration collects the options passed to Register.
+type registration struct {
+	desc   task.Descriptor
+	limits Limits
+}
+
+// WithLimits sets the queue and execution limits of the task type.
+func WithLimits(limits Limits) RegisterOption {
+	return func(r *registration) {
+		r.limits = limits
+	}
+}
+
+// WithDescription sets the description of the task type shown by discovery.
+func WithDescription(description string) RegisterOption {
+	return func(r *registration) {
+		r.desc.Description = description
+	}
+}
+
+// WithParamsSchema sets the JSON schema of the task parameters shown by discovery.
+func WithParamsSchema(schema json.RawMessage) RegisterOption {
+	return func(r *registration) {
+		r.desc.ParamsSchema = schema
+	}
+}
+
+// Register adds a task type executed by the factory.
+// Registering an existing type again has no effect.
+func (r *Runner) Register(taskType string, factory Factory, opts ...RegisterOption) error {
+	if taskType == "" || factory == nil {
+		return fmt.Errorf("cannot register task type %q: type and factory are required", taskType)
+	}
+	if r.closed.Load() {
+		return fmt.Errorf("cannot register task type %q: %w", taskType, ErrClosed)
+	}
+
+	reg := registration{desc: task.Descriptor{Type: taskType, Factory: factory}, limits: r.defaults}
+	for _, opt := range opts {
+		opt(&reg)
+	}
+
+	r.manager.Register(reg.desc, reg.limits)
+
+	return nil
+}
+
+// Submit queues a new task of the given type. Params (optional) are encoded as the JSON task payload;
+// a json.RawMessage is used as is.
+func (r *Runner) Submit(taskType string, params any) (*Task, error) {
+	var payload json.RawMessage
+	switch p := params.(type) {
+	case nil:
+	case json.RawMessage:
+		payload = p
+	default:
+		data, err := json.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode params of task type %q: %w", taskType, err)
+		}
+		payload = data
+	}
+
+	return r.manager.CreateTaskWithParams(taskType, payload)
+}
+
+// Get returns a snapshot of the task with the given ID.
+func (r *Runner) Get(id string) (*Task, error) {
+	return r.manager.GetTask(id)
+}
+
+// Cancel cancels a pending or running task.
+func (r *Runner) Cancel(id string) error {
+	return r.manager.CancelTask(context.Background(), id)
+}
+
+// Retry queues a finished task again and returns a snapshot of the queued task. By default a new task
+// linked by RetryOf is created; with inPlace the same task is re-queued and its last run kept in Attempts.
+func (r *Runner) Retry(id string, inPlace bool) (*Task, error) {
+	return r.manager.RetryTask(context.Background(), id, inPlace)
+}
+
+// Wait blocks until the task reaches a final status or ctx is done, and returns its snapshot.
+func (r *Runner) Wait(ctx context.Context, id string) (*Task, error) {
+	return r.manager.WaitTask(ctx, id)
+}
+
+// Types returns the registered task types with their limits and live statistics.
+func (r *Runner) Types() []*TaskType {
+	return r.manager.ListTaskTypes()
+}
+
+// Shutdown stops accepting tasks and waits for the queued and running ones to finish.
+// If ctx is done first, the remaining tasks are canceled and the context error is returned.
+func (r *Runner) Shutdown(ctx context.Context) error {
+	r.closed.Store(true)
+	return r.manager.Shutdown(ctx)
+}
+
+// Handler returns the HTTP API of the runner for mounting under the given path prefix
+// (e.g. "/runner"); an empty prefix serves the API at the root.
+func (r *Runner) Handler(prefix string) http.Handler {
+	h := router.InitTaskRouter(handler.NewTaskHandler(r.manager))
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return h
+	}
+
+	return http.StripPrefix(prefix, h)
+}
@@ -0,0 +1,110 @@
+package runner_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kylerqws/task-runner/runner"
+)
+
+type (
+	upperFactory struct{}                      // upperFactory creates tasks that upper-case their text parameter.
+	upperTask    struct{ text, result string } // upperTask upper-cases its text.
+	upperParams  struct {
+		Text string `json:"text"`
+	} // upperParams holds the parameters of an upper task.
+	upperOutput struct {
+		Result string `json:"result"`
+	} // upperOutput holds the output of an upper task.
+)
+
+// New returns an upper task for the task parameters.
+func (*upperFactory) New(t *runner.Task) runner.ExecutableTask {
+	var p upperParams
+	_ = json.Unmarshal(t.Params, &p)
+	return &upperTask{text: p.Text}
+}
+
+// ValidateParams requires a non-empty text.
+func (*upperFactory) ValidateParams(params json.RawMessage) error {
+	var p upperParams
+	if err := json.Unmarshal(params, &p); err != nil || p.Text == "" {
+		return errors.New("text is required")
+	}
+	return nil
+}
+
+// Run upper-cases the text.
+func (t *upperTask) Run(_ context.Context) error {
+	t.result = strings.ToUpper(t.text)
+	return nil
+}
+
+// Output returns the upper-cased text.
+func (t *upperTask) Output() any {
+	return upperOutput{Result: t.result}
+}
+
+// TestRunner_SubmitWait checks that a custom task type runs in-process and stores its output.
+func TestRunner_SubmitWait(t *testing.T) {
+	r := runner.New()
+	if err := r.Register("upper", &upperFactory{}, runner.WithDescription("Upper-cases text")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := r.Submit("upper", nil); !errors.Is(err, runner.ErrTaskInvalidParams) {
+		t.Errorf("expected ErrTaskInvalidParams, got %v", err)
+	}
+
+	tsk, err := r.Submit("upper", upperParams{Text: "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done, err := r.Wait(context.Background(), tsk.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out upperOutput
+	if err := json.Unmarshal(done.Output, &out); err != nil || done.Status != runner.TaskStatusDone || out.Result != "HELLO" {
+		t.Errorf("unexpected task: %+v", done)
+	}
+}
+
+// TestRunner_Handler checks that the HTTP API can be mounted under a prefix.
+func TestRunner_Handler(t *testing.T) {
+	r := runner.New()
+	_ = r.Register("upper", &upperFactory{})
+	tsk, _ := r.Submit("upper", upperParams{Text: "hi"})
+
+	mux := http.NewServeMux()
+	mux.Handle("/runner/", r.Handler("/runner"))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/runner/tasks/"+tsk.ID, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), tsk.ID) {
+		t.Errorf("expected the task to be served under the prefix, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+// TestRunner_Shutdown checks that a shut down runner rejects new types and tasks.
+func TestRunner_Shutdown(t *testing.T) {
+	r := runner.New()
+	_ = r.Register("upper", &upperFactory{})
+
+	if err := r.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Submit("upper", upperParams{Text: "late"}); !errors.Is(err, runner.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+	if err := r.Register("other", &upperFactory{}); !errors.Is(err, runner.ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
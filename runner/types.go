@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"github.com/kylerqws/task-runner/internal/domain/model"
+	"github.com/kylerqws/task-runner/internal/domain/service"
+	"github.com/kylerqws/task-runner/internal/domain/task"
+)
+
+// Task holds metadata about an asynchronous task's lifecycle and result.
+type Task = model.Task
+
+// TaskStatus represents the current status of a task.
+type TaskStatus = model.TaskStatus
+
+// Task statuses.
+const (
+	TaskStatusPending  = model.TaskStatusPending
+	TaskStatusRunning  = model.TaskStatusRunning
+	TaskStatusDone     = model.TaskStatusDone
+	TaskStatusFailed   = model.TaskStatusFailed
+	TaskStatusCanceled = model.TaskStatusCanceled
+)
+
+// TaskAttempt records a previous run of a task retried in place.
+type TaskAttempt = model.TaskAttempt
+
+// TaskType describes a registered task type with its limits and live statistics.
+type TaskType = model.TaskType
+
+// ExecutableTask is a task that can be run by the runner.
+// Run receives a context that is cancelled when the task is canceled or exceeds its timeout.
+type ExecutableTask = task.ExecutableTask
+
+// Factory creates an ExecutableTask for every submitted task of its type.
+type Factory = task.Factory
+
+// ParamsValidator is optionally implemented by a Factory to reject invalid task parameters on Submit.
+type ParamsValidator = task.ParamsValidator
+
+// OutputTask is optionally implemented by an ExecutableTask to store structured output in the task.
+type OutputTask = task.OutputTask
+
+// Limits holds the queue and execution limits of a task type.
+type Limits = service.TypeConfig
+
+// Errors returned by the Runner methods. They can be matched with errors.Is.
+var (
+	ErrTaskNotFound          = service.ErrTaskNotFound
+	ErrTaskInProgress        = service.ErrTaskInProgress
+	ErrTaskQueueLimitReached = service.ErrTaskQueueLimitReached
+	ErrTaskUnknownType       = service.ErrTaskUnknownType
+	ErrTaskTypeDisabled      = service.ErrTaskTypeDisabled
+	ErrTaskInvalidParams     = service.ErrTaskInvalidParams
+	ErrTaskFinished          = service.ErrTaskFinished
+	ErrClosed                = service.ErrTaskManagerClosed
+	ErrTaskTypePaused        = service.ErrTaskTypePaused
+	ErrTaskNotFinished       = service.ErrTaskNotFinished
+)